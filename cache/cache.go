@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -10,6 +11,32 @@ import (
 type Client interface {
 	Get(ctx context.Context, key string) (string, error)
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	Del(ctx context.Context, keys ...string) error
+	Rename(ctx context.Context, key string, newKey string) error
+	ZAdd(ctx context.Context, key string, members ...Member) error
+	ZAddGT(ctx context.Context, key string, members ...Member) error
+	ZIncrBy(ctx context.Context, key string, increment float64, member string) error
+	ZRevRange(ctx context.Context, key string, start int64, stop int64) ([]Member, error)
+	ZRevRank(ctx context.Context, key string, member string) (int64, bool, error)
+	Keys(ctx context.Context, pattern string) ([]string, error)
+	TxPipelined(ctx context.Context, fn func(tx Tx)) error
+}
+
+// Member is a member of a sorted set along with its score.
+type Member struct {
+	Name  string
+	Score float64
+}
+
+// Tx queues writes to be sent to Redis together, in a single transaction.
+type Tx interface {
+	ZAddGT(key string, members ...Member)
+	ZIncrBy(key string, increment float64, member string)
+}
+
+type tx struct {
+	ctx  context.Context
+	pipe redis.Pipeliner
 }
 
 type client struct {
@@ -47,3 +74,114 @@ func (c client) Set(ctx context.Context, key string, value interface{}, expirati
 
 	return nil
 }
+
+func (c client) Del(ctx context.Context, keys ...string) error {
+	return c.client.Del(ctx, keys...).Err()
+}
+
+func (c client) Rename(ctx context.Context, key string, newKey string) error {
+	return c.client.Rename(ctx, key, newKey).Err()
+}
+
+func (c client) ZAdd(ctx context.Context, key string, members ...Member) error {
+	return c.client.ZAddArgs(ctx, key, redis.ZAddArgs{
+		Members: toZ(members),
+	}).Err()
+}
+
+// ZAddGT adds the members to the sorted set, only replacing the score of
+// the ones already in it when the new score is greater.
+func (c client) ZAddGT(ctx context.Context, key string, members ...Member) error {
+	return c.client.ZAddArgs(ctx, key, redis.ZAddArgs{
+		GT:      true,
+		Members: toZ(members),
+	}).Err()
+}
+
+func (c client) ZIncrBy(ctx context.Context, key string, increment float64, member string) error {
+	return c.client.ZIncrBy(ctx, key, increment, member).Err()
+}
+
+// ZRevRange lists the members of the sorted set from the highest score,
+// from the start rank to the stop rank, both included.
+func (c client) ZRevRange(ctx context.Context, key string, start int64, stop int64) ([]Member, error) {
+	values, err := c.client.ZRevRangeWithScores(ctx, key, start, stop).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]Member, 0, len(values))
+
+	for _, value := range values {
+		members = append(members, Member{
+			Name:  fmt.Sprint(value.Member),
+			Score: value.Score,
+		})
+	}
+
+	return members, nil
+}
+
+// ZRevRank is the rank of the member in the sorted set, counting from zero
+// at the highest score. It reports false when the member is not in the set.
+func (c client) ZRevRank(ctx context.Context, key string, member string) (int64, bool, error) {
+	rank, err := c.client.ZRevRank(ctx, key, member).Result()
+	if err == redis.Nil {
+		return 0, false, nil
+	}
+
+	if err != nil {
+		return 0, false, err
+	}
+
+	return rank, true, nil
+}
+
+// Keys lists the keys matching pattern. The keys are walked with SCAN, so
+// Redis is not blocked while they are listed.
+func (c client) Keys(ctx context.Context, pattern string) ([]string, error) {
+	keys := make([]string, 0)
+
+	iter := c.client.Scan(ctx, 0, pattern, 0).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// TxPipelined sends the writes fn queues on tx in a single MULTI/EXEC
+// transaction, so either all of them are applied or none is.
+func (c client) TxPipelined(ctx context.Context, fn func(tx Tx)) error {
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		fn(tx{ctx, pipe})
+		return nil
+	})
+
+	return err
+}
+
+func (t tx) ZAddGT(key string, members ...Member) {
+	t.pipe.ZAddArgs(t.ctx, key, redis.ZAddArgs{
+		GT:      true,
+		Members: toZ(members),
+	})
+}
+
+func (t tx) ZIncrBy(key string, increment float64, member string) {
+	t.pipe.ZIncrBy(t.ctx, key, increment, member)
+}
+
+func toZ(members []Member) []redis.Z {
+	z := make([]redis.Z, 0, len(members))
+
+	for _, member := range members {
+		z = append(z, redis.Z{Score: member.Score, Member: member.Name})
+	}
+
+	return z
+}
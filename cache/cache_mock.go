@@ -35,6 +35,25 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
+// Del mocks base method.
+func (m *MockClient) Del(ctx context.Context, keys ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Del", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Del indicates an expected call of Del.
+func (mr *MockClientMockRecorder) Del(ctx interface{}, keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockClient)(nil).Del), varargs...)
+}
+
 // Get mocks base method.
 func (m *MockClient) Get(ctx context.Context, key string) (string, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +69,35 @@ func (mr *MockClientMockRecorder) Get(ctx, key interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockClient)(nil).Get), ctx, key)
 }
 
+// Keys mocks base method.
+func (m *MockClient) Keys(ctx context.Context, pattern string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Keys", ctx, pattern)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Keys indicates an expected call of Keys.
+func (mr *MockClientMockRecorder) Keys(ctx, pattern interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keys", reflect.TypeOf((*MockClient)(nil).Keys), ctx, pattern)
+}
+
+// Rename mocks base method.
+func (m *MockClient) Rename(ctx context.Context, key, newKey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rename", ctx, key, newKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rename indicates an expected call of Rename.
+func (mr *MockClientMockRecorder) Rename(ctx, key, newKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rename", reflect.TypeOf((*MockClient)(nil).Rename), ctx, key, newKey)
+}
+
 // Set mocks base method.
 func (m *MockClient) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
 	m.ctrl.T.Helper()
@@ -63,3 +111,152 @@ func (mr *MockClientMockRecorder) Set(ctx, key, value, expiration interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockClient)(nil).Set), ctx, key, value, expiration)
 }
+
+// TxPipelined mocks base method.
+func (m *MockClient) TxPipelined(ctx context.Context, fn func(Tx)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TxPipelined", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TxPipelined indicates an expected call of TxPipelined.
+func (mr *MockClientMockRecorder) TxPipelined(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TxPipelined", reflect.TypeOf((*MockClient)(nil).TxPipelined), ctx, fn)
+}
+
+// ZAdd mocks base method.
+func (m *MockClient) ZAdd(ctx context.Context, key string, members ...Member) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ZAdd", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ZAdd indicates an expected call of ZAdd.
+func (mr *MockClientMockRecorder) ZAdd(ctx, key interface{}, members ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZAdd", reflect.TypeOf((*MockClient)(nil).ZAdd), varargs...)
+}
+
+// ZAddGT mocks base method.
+func (m *MockClient) ZAddGT(ctx context.Context, key string, members ...Member) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ZAddGT", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ZAddGT indicates an expected call of ZAddGT.
+func (mr *MockClientMockRecorder) ZAddGT(ctx, key interface{}, members ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZAddGT", reflect.TypeOf((*MockClient)(nil).ZAddGT), varargs...)
+}
+
+// ZIncrBy mocks base method.
+func (m *MockClient) ZIncrBy(ctx context.Context, key string, increment float64, member string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZIncrBy", ctx, key, increment, member)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ZIncrBy indicates an expected call of ZIncrBy.
+func (mr *MockClientMockRecorder) ZIncrBy(ctx, key, increment, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZIncrBy", reflect.TypeOf((*MockClient)(nil).ZIncrBy), ctx, key, increment, member)
+}
+
+// ZRevRange mocks base method.
+func (m *MockClient) ZRevRange(ctx context.Context, key string, start, stop int64) ([]Member, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRevRange", ctx, key, start, stop)
+	ret0, _ := ret[0].([]Member)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRevRange indicates an expected call of ZRevRange.
+func (mr *MockClientMockRecorder) ZRevRange(ctx, key, start, stop interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRevRange", reflect.TypeOf((*MockClient)(nil).ZRevRange), ctx, key, start, stop)
+}
+
+// ZRevRank mocks base method.
+func (m *MockClient) ZRevRank(ctx context.Context, key, member string) (int64, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRevRank", ctx, key, member)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ZRevRank indicates an expected call of ZRevRank.
+func (mr *MockClientMockRecorder) ZRevRank(ctx, key, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRevRank", reflect.TypeOf((*MockClient)(nil).ZRevRank), ctx, key, member)
+}
+
+// MockTx is a mock of Tx interface.
+type MockTx struct {
+	ctrl     *gomock.Controller
+	recorder *MockTxMockRecorder
+}
+
+// MockTxMockRecorder is the mock recorder for MockTx.
+type MockTxMockRecorder struct {
+	mock *MockTx
+}
+
+// NewMockTx creates a new mock instance.
+func NewMockTx(ctrl *gomock.Controller) *MockTx {
+	mock := &MockTx{ctrl: ctrl}
+	mock.recorder = &MockTxMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTx) EXPECT() *MockTxMockRecorder {
+	return m.recorder
+}
+
+// ZAddGT mocks base method.
+func (m *MockTx) ZAddGT(key string, members ...Member) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "ZAddGT", varargs...)
+}
+
+// ZAddGT indicates an expected call of ZAddGT.
+func (mr *MockTxMockRecorder) ZAddGT(key interface{}, members ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZAddGT", reflect.TypeOf((*MockTx)(nil).ZAddGT), varargs...)
+}
+
+// ZIncrBy mocks base method.
+func (m *MockTx) ZIncrBy(key string, increment float64, member string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ZIncrBy", key, increment, member)
+}
+
+// ZIncrBy indicates an expected call of ZIncrBy.
+func (mr *MockTxMockRecorder) ZIncrBy(key, increment, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZIncrBy", reflect.TypeOf((*MockTx)(nil).ZIncrBy), key, increment, member)
+}
@@ -0,0 +1,52 @@
+// Command rebuild_leaderboards recomputes every leaderboard from the match
+// history, for when Redis lost them or they drifted from the database.
+package main
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"os"
+
+	"github.com/Maycon-Santos/go-snake-backend/cache"
+	"github.com/Maycon-Santos/go-snake-backend/db"
+	"github.com/Maycon-Santos/go-snake-backend/leaderboard"
+	"github.com/Maycon-Santos/go-snake-backend/process"
+)
+
+func main() {
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	ctx := context.Background()
+
+	env, err := process.NewEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dbConn, err := db.NewConnection(env)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer dbConn.Close()
+
+	cacheClient, err := cache.NewClient(ctx, env.RedisAddress)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	matchesRepository := db.NewMatchesRepository(dbConn)
+	leaderboards := leaderboard.NewLeaderboards(cacheClient)
+
+	stats, err := matchesRepository.ListMonthlyStats(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err = leaderboards.Rebuild(ctx, stats); err != nil {
+		log.Fatal(err)
+	}
+
+	slog.Info("leaderboards rebuilt", "rows", len(stats))
+}
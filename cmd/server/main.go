@@ -3,16 +3,23 @@ package main
 import (
 	"context"
 	"log"
+	"log/slog"
+	"os"
 
 	"github.com/Maycon-Santos/go-snake-backend/cache"
 	"github.com/Maycon-Santos/go-snake-backend/container"
 	"github.com/Maycon-Santos/go-snake-backend/db"
 	"github.com/Maycon-Santos/go-snake-backend/game"
+	"github.com/Maycon-Santos/go-snake-backend/leaderboard"
+	"github.com/Maycon-Santos/go-snake-backend/matchmaking"
 	"github.com/Maycon-Santos/go-snake-backend/process"
 	"github.com/Maycon-Santos/go-snake-backend/server"
+	"github.com/Maycon-Santos/go-snake-backend/server/routes"
 )
 
 func main() {
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
 	env, err := process.NewEnv()
 	if err != nil {
 		log.Fatal(err)
@@ -27,12 +34,31 @@ func main() {
 
 	accountsRepository := db.NewAccountsRepository(dbConn)
 	skinsRepository := db.NewSkinsRepository(dbConn)
+	replaysRepository := db.NewReplaysRepository(dbConn)
+	mapsRepository := db.NewMapsRepository(dbConn)
+	matchesRepository := db.NewMatchesRepository(dbConn)
+	ratingsRepository := db.NewRatingsRepository(dbConn)
+	seriesRepository := db.NewSeriesRepository(dbConn)
 
 	cacheClient, err := cache.NewClient(context.Background(), env.RedisAddress)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	leaderboards := leaderboard.NewLeaderboards(cacheClient)
+
+	queue := matchmaking.NewQueue(matchmaking.Config{
+		Modes:          matchmaking.DefaultModes,
+		RatingBand:     env.Matchmaking.RatingBand,
+		BandGrowth:     env.Matchmaking.BandGrowth,
+		MaxRatingBand:  env.Matchmaking.MaxRatingBand,
+		AnyRegionAfter: env.Matchmaking.AnyRegionAfter,
+		Timeout:        env.Matchmaking.Timeout,
+		Interval:       env.Matchmaking.Interval,
+	})
+
+	defer queue.Close()
+
 	dependenciesContainer := container.New()
 	matches := game.NewMatches()
 
@@ -41,12 +67,21 @@ func main() {
 		&cacheClient,
 		&accountsRepository,
 		&skinsRepository,
+		&replaysRepository,
+		&mapsRepository,
+		&matchesRepository,
+		&ratingsRepository,
+		&seriesRepository,
+		&leaderboards,
+		&queue,
 		&matches,
 	)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	queue.OnGroup(routes.MatchmakingGroups(dependenciesContainer))
+
 	err = server.Listen(dependenciesContainer)
 	if err != nil {
 		log.Fatal(err)
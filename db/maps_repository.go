@@ -0,0 +1,192 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type MapsRepository interface {
+	Save(ctx context.Context, ownerID string, name string, playersLimit int, definition []byte) (string, error)
+	Update(ctx context.Context, id string, ownerID string, name string, playersLimit int, definition []byte) (bool, error)
+	Publish(ctx context.Context, id string, ownerID string) (bool, error)
+	Delete(ctx context.Context, id string, ownerID string) (bool, error)
+	GetByID(ctx context.Context, id string) (*Map, error)
+	List(ctx context.Context, accountID string, onlyOwned bool, limit int, offset int) ([]Map, error)
+}
+
+type mapsRepository struct {
+	dbConn *sql.DB
+}
+
+// Map is a map created with the map editor. Definition holds the JSON
+// encoding of a game.Map. Only published maps can be used by other accounts,
+// and they can no longer be changed.
+type Map struct {
+	ID           string
+	OwnerID      string
+	Name         string
+	PlayersLimit int
+	Definition   []byte
+	Published    bool
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+const mapColumns = "id, owner_id, name, players_limit, definition, published, created_at, updated_at"
+
+func NewMapsRepository(dbConn *sql.DB) MapsRepository {
+	return mapsRepository{dbConn}
+}
+
+func (mr mapsRepository) Save(ctx context.Context, ownerID string, name string, playersLimit int, definition []byte) (string, error) {
+	stmt, err := mr.dbConn.PrepareContext(
+		ctx,
+		"INSERT INTO maps (owner_id, name, players_limit, definition) VALUES ($1, $2, $3, $4) RETURNING id",
+	)
+	if err != nil {
+		return "", err
+	}
+	defer stmt.Close()
+
+	var mapID string
+	err = stmt.QueryRowContext(ctx, ownerID, name, playersLimit, definition).Scan(&mapID)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprint(mapID), nil
+}
+
+func (mr mapsRepository) Update(ctx context.Context, id string, ownerID string, name string, playersLimit int, definition []byte) (bool, error) {
+	result, err := mr.dbConn.ExecContext(
+		ctx,
+		"UPDATE maps SET name=$3, players_limit=$4, definition=$5, updated_at=CURRENT_TIMESTAMP WHERE id=$1 AND owner_id=$2 AND NOT published",
+		id,
+		ownerID,
+		name,
+		playersLimit,
+		definition,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return affected(result)
+}
+
+func (mr mapsRepository) Publish(ctx context.Context, id string, ownerID string) (bool, error) {
+	result, err := mr.dbConn.ExecContext(
+		ctx,
+		"UPDATE maps SET published=TRUE, updated_at=CURRENT_TIMESTAMP WHERE id=$1 AND owner_id=$2",
+		id,
+		ownerID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return affected(result)
+}
+
+func (mr mapsRepository) Delete(ctx context.Context, id string, ownerID string) (bool, error) {
+	result, err := mr.dbConn.ExecContext(ctx, "DELETE FROM maps WHERE id=$1 AND owner_id=$2", id, ownerID)
+	if err != nil {
+		return false, err
+	}
+
+	return affected(result)
+}
+
+func (mr mapsRepository) GetByID(ctx context.Context, id string) (*Map, error) {
+	row := mr.dbConn.QueryRowContext(ctx, "SELECT "+mapColumns+" FROM maps WHERE id=$1", id)
+
+	_map, err := scanMap(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return _map, nil
+}
+
+// List returns the maps published by anyone along with the drafts of
+// accountID, or only the maps of accountID when onlyOwned is set, newest
+// first.
+func (mr mapsRepository) List(ctx context.Context, accountID string, onlyOwned bool, limit int, offset int) ([]Map, error) {
+	rows, err := mr.dbConn.QueryContext(
+		ctx,
+		"SELECT "+mapColumns+" FROM maps WHERE owner_id=$1 OR (published AND NOT $2) ORDER BY updated_at DESC, id DESC LIMIT $3 OFFSET $4",
+		accountID,
+		onlyOwned,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	maps := make([]Map, 0)
+
+	for rows.Next() {
+		var _map *Map
+
+		_map, err = scanMap(rows)
+		if err != nil {
+			break
+		}
+
+		maps = append(maps, *_map)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		return nil, closeErr
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return maps, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanMap(row scanner) (*Map, error) {
+	var _map Map
+
+	err := row.Scan(
+		&_map.ID,
+		&_map.OwnerID,
+		&_map.Name,
+		&_map.PlayersLimit,
+		&_map.Definition,
+		&_map.Published,
+		&_map.CreatedAt,
+		&_map.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &_map, nil
+}
+
+func affected(result sql.Result) (bool, error) {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
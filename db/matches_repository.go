@@ -0,0 +1,321 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type MatchesRepository interface {
+	Save(ctx context.Context, match Match) (string, error)
+	ListByAccountID(ctx context.Context, accountID string, limit int, offset int) ([]Match, error)
+	GetAccountStats(ctx context.Context, accountID string) (*AccountStats, error)
+	ListMonthlyStats(ctx context.Context) ([]MonthlyStats, error)
+}
+
+type matchesRepository struct {
+	dbConn *sql.DB
+}
+
+// Match is a round that was played to the end. GameID is the id of the
+// match the players were in, which may have played several rounds. Map and
+// Settings hold the JSON the round was played with.
+type Match struct {
+	ID        string
+	GameID    string
+	Map       []byte
+	Settings  []byte
+	Ticks     int
+	Duration  time.Duration
+	StartedAt time.Time
+	EndedAt   time.Time
+	Players   []MatchPlayer
+}
+
+// MatchPlayer is how an account did in a round. DeathCause and KillerID are
+// empty when they do not apply.
+type MatchPlayer struct {
+	AccountID     string
+	Placement     int
+	Length        int
+	FoodEaten     int
+	Kills         int
+	Bonus         int
+	SurvivalTicks int
+	DeathCause    string
+	KillerID      string
+}
+
+// AccountStats sums up the rounds an account played. A round is won by the
+// player placed first.
+type AccountStats struct {
+	Matches          int
+	Wins             int
+	BestLength       int
+	Kills            int
+	FoodEaten        int
+	AveragePlacement float64
+}
+
+// MonthlyStats sums up the rounds an account played that ended in Month,
+// which is the first instant of the month in UTC.
+type MonthlyStats struct {
+	AccountID  string
+	Month      time.Time
+	Wins       int
+	BestLength int
+	Kills      int
+}
+
+func NewMatchesRepository(dbConn *sql.DB) MatchesRepository {
+	return matchesRepository{dbConn}
+}
+
+func (mr matchesRepository) Save(ctx context.Context, match Match) (string, error) {
+	tx, err := mr.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var matchID string
+
+	err = tx.QueryRowContext(
+		ctx,
+		"INSERT INTO matches (game_id, map, settings, ticks, duration_ms, started_at, ended_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id",
+		match.GameID,
+		match.Map,
+		match.Settings,
+		match.Ticks,
+		match.Duration.Milliseconds(),
+		match.StartedAt,
+		match.EndedAt,
+	).Scan(&matchID)
+	if err != nil {
+		return "", err
+	}
+
+	stmt, err := tx.PrepareContext(
+		ctx,
+		"INSERT INTO match_players (match_id, account_id, placement, length, food_eaten, kills, bonus, survival_ticks, death_cause, killer_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
+	)
+	if err != nil {
+		return "", err
+	}
+	defer stmt.Close()
+
+	for _, player := range match.Players {
+		_, err = stmt.ExecContext(
+			ctx,
+			matchID,
+			player.AccountID,
+			player.Placement,
+			player.Length,
+			player.FoodEaten,
+			player.Kills,
+			player.Bonus,
+			player.SurvivalTicks,
+			nullString(player.DeathCause),
+			nullString(player.KillerID),
+		)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprint(matchID), nil
+}
+
+// ListByAccountID returns the rounds the account played, the latest first,
+// along with how every player of each round did.
+func (mr matchesRepository) ListByAccountID(ctx context.Context, accountID string, limit int, offset int) ([]Match, error) {
+	rows, err := mr.dbConn.QueryContext(
+		ctx,
+		`SELECT m.id, m.game_id, m.map, m.settings, m.ticks, m.duration_ms, m.started_at, m.ended_at
+		FROM matches m JOIN match_players mp ON mp.match_id = m.id
+		WHERE mp.account_id=$1
+		ORDER BY m.ended_at DESC, m.id DESC LIMIT $2 OFFSET $3`,
+		accountID,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches := make([]Match, 0)
+	ids := make([]string, 0)
+
+	for rows.Next() {
+		var (
+			match    Match
+			duration int64
+		)
+
+		err = rows.Scan(
+			&match.ID,
+			&match.GameID,
+			&match.Map,
+			&match.Settings,
+			&match.Ticks,
+			&duration,
+			&match.StartedAt,
+			&match.EndedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		match.Duration = time.Duration(duration) * time.Millisecond
+		match.Players = make([]MatchPlayer, 0)
+
+		matches = append(matches, match)
+		ids = append(ids, match.ID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(matches) == 0 {
+		return matches, nil
+	}
+
+	players, err := mr.listPlayers(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range matches {
+		matches[i].Players = append(matches[i].Players, players[matches[i].ID]...)
+	}
+
+	return matches, nil
+}
+
+func (mr matchesRepository) listPlayers(ctx context.Context, matchIDs []string) (map[string][]MatchPlayer, error) {
+	rows, err := mr.dbConn.QueryContext(
+		ctx,
+		`SELECT match_id, account_id, placement, length, food_eaten, kills, bonus, survival_ticks, COALESCE(death_cause, ''), COALESCE(killer_id::TEXT, '')
+		FROM match_players WHERE match_id = ANY($1::INT[])
+		ORDER BY placement, account_id`,
+		pq.Array(matchIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	players := make(map[string][]MatchPlayer)
+
+	for rows.Next() {
+		var (
+			matchID string
+			player  MatchPlayer
+		)
+
+		err = rows.Scan(
+			&matchID,
+			&player.AccountID,
+			&player.Placement,
+			&player.Length,
+			&player.FoodEaten,
+			&player.Kills,
+			&player.Bonus,
+			&player.SurvivalTicks,
+			&player.DeathCause,
+			&player.KillerID,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		players[matchID] = append(players[matchID], player)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return players, nil
+}
+
+func (mr matchesRepository) GetAccountStats(ctx context.Context, accountID string) (*AccountStats, error) {
+	row := mr.dbConn.QueryRowContext(
+		ctx,
+		`SELECT COUNT(*), COUNT(*) FILTER (WHERE placement = 1), COALESCE(MAX(length), 0),
+		COALESCE(SUM(kills), 0), COALESCE(SUM(food_eaten), 0), COALESCE(AVG(placement), 0)
+		FROM match_players WHERE account_id=$1`,
+		accountID,
+	)
+
+	var stats AccountStats
+
+	err := row.Scan(
+		&stats.Matches,
+		&stats.Wins,
+		&stats.BestLength,
+		&stats.Kills,
+		&stats.FoodEaten,
+		&stats.AveragePlacement,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// ListMonthlyStats sums up the rounds of every account month by month.
+func (mr matchesRepository) ListMonthlyStats(ctx context.Context) ([]MonthlyStats, error) {
+	rows, err := mr.dbConn.QueryContext(
+		ctx,
+		`SELECT mp.account_id, date_trunc('month', m.ended_at), COUNT(*) FILTER (WHERE mp.placement = 1),
+		MAX(mp.length), SUM(mp.kills)
+		FROM match_players mp JOIN matches m ON m.id = mp.match_id
+		GROUP BY mp.account_id, date_trunc('month', m.ended_at)
+		ORDER BY 2, 1`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]MonthlyStats, 0)
+
+	for rows.Next() {
+		var monthly MonthlyStats
+
+		err = rows.Scan(
+			&monthly.AccountID,
+			&monthly.Month,
+			&monthly.Wins,
+			&monthly.BestLength,
+			&monthly.Kills,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		monthly.Month = time.Date(monthly.Month.Year(), monthly.Month.Month(), 1, 0, 0, 0, 0, time.UTC)
+		stats = append(stats, monthly)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS replays (
+  id SERIAL PRIMARY KEY,
+  match_id VARCHAR (20) NOT NULL,
+  log JSONB NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS replays_match_id_idx ON replays (match_id);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS maps (
+  id SERIAL PRIMARY KEY,
+  owner_id INT NOT NULL REFERENCES accounts(id),
+  name VARCHAR (30) NOT NULL,
+  players_limit INT NOT NULL,
+  definition JSONB NOT NULL,
+  published BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS maps_owner_id_idx ON maps (owner_id);
+CREATE INDEX IF NOT EXISTS maps_published_idx ON maps (published);
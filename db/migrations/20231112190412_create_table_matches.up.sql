@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS matches (
+  id SERIAL PRIMARY KEY,
+  game_id VARCHAR (20) NOT NULL,
+  map JSONB NOT NULL,
+  settings JSONB NOT NULL,
+  ticks INT NOT NULL,
+  duration_ms INT NOT NULL,
+  started_at TIMESTAMP NOT NULL,
+  ended_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS matches_game_id_idx ON matches (game_id);
+
+CREATE TABLE IF NOT EXISTS match_players (
+  match_id INT NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+  account_id INT NOT NULL REFERENCES accounts(id),
+  placement INT NOT NULL,
+  length INT NOT NULL,
+  food_eaten INT NOT NULL,
+  kills INT NOT NULL,
+  survival_ticks INT NOT NULL,
+  death_cause VARCHAR (10),
+  killer_id INT REFERENCES accounts(id),
+  PRIMARY KEY (match_id, account_id)
+);
+
+CREATE INDEX IF NOT EXISTS match_players_account_id_idx ON match_players (account_id);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS ratings (
+  account_id INT PRIMARY KEY REFERENCES accounts(id),
+  rating DOUBLE PRECISION NOT NULL,
+  matches INT NOT NULL DEFAULT 0,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS rating_history (
+  id SERIAL PRIMARY KEY,
+  account_id INT NOT NULL REFERENCES accounts(id),
+  match_id INT NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+  rating_before DOUBLE PRECISION NOT NULL,
+  rating_after DOUBLE PRECISION NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS rating_history_account_id_idx ON rating_history (account_id);
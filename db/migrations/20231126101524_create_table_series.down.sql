@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS series_players;
+DROP TABLE IF EXISTS series;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS series (
+  id SERIAL PRIMARY KEY,
+  game_id VARCHAR (20) NOT NULL,
+  length INT NOT NULL,
+  rounds INT NOT NULL,
+  winner_id INT NOT NULL REFERENCES accounts(id),
+  ended_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS series_game_id_idx ON series (game_id);
+
+CREATE TABLE IF NOT EXISTS series_players (
+  series_id INT NOT NULL REFERENCES series(id) ON DELETE CASCADE,
+  account_id INT NOT NULL REFERENCES accounts(id),
+  points INT NOT NULL,
+  PRIMARY KEY (series_id, account_id)
+);
+
+CREATE INDEX IF NOT EXISTS series_players_account_id_idx ON series_players (account_id);
@@ -0,0 +1,5 @@
+ALTER TABLE series_players DROP COLUMN IF EXISTS team;
+
+DELETE FROM series WHERE winner_id IS NULL;
+ALTER TABLE series DROP COLUMN IF EXISTS winner_team;
+ALTER TABLE series ALTER COLUMN winner_id SET NOT NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE series ALTER COLUMN winner_id DROP NOT NULL;
+ALTER TABLE series ADD COLUMN IF NOT EXISTS winner_team VARCHAR (10);
+
+ALTER TABLE series_players ADD COLUMN IF NOT EXISTS team VARCHAR (10);
@@ -0,0 +1 @@
+ALTER TABLE match_players DROP COLUMN IF EXISTS bonus;
@@ -0,0 +1 @@
+ALTER TABLE match_players ADD COLUMN IF NOT EXISTS bonus INT NOT NULL DEFAULT 0;
@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type RatingsRepository interface {
+	GetByAccountID(ctx context.Context, accountID string) (*Rating, error)
+	Update(ctx context.Context, matchID string, accountIDs []string, initial float64, rate func(ratings []Rating) []Rating) error
+}
+
+type ratingsRepository struct {
+	dbConn *sql.DB
+}
+
+// Rating is the skill rating of an account and how many rated rounds it
+// is based on.
+type Rating struct {
+	AccountID string
+	Rating    float64
+	Matches   int
+	UpdatedAt time.Time
+}
+
+func NewRatingsRepository(dbConn *sql.DB) RatingsRepository {
+	return ratingsRepository{dbConn}
+}
+
+func (rr ratingsRepository) GetByAccountID(ctx context.Context, accountID string) (*Rating, error) {
+	row := rr.dbConn.QueryRowContext(
+		ctx,
+		"SELECT account_id, rating, matches, updated_at FROM ratings WHERE account_id=$1",
+		accountID,
+	)
+
+	var rating Rating
+
+	err := row.Scan(&rating.AccountID, &rating.Rating, &rating.Matches, &rating.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &rating, nil
+}
+
+// Update rates the accounts that played the round matchID. The ratings of
+// the accounts, starting at initial for the ones never rated, are locked
+// and handed to rate, which returns them with the new ratings. They are
+// saved along with the history of each change in a single transaction, so
+// rounds that end at the same time do not overwrite each other.
+func (rr ratingsRepository) Update(ctx context.Context, matchID string, accountIDs []string, initial float64, rate func(ratings []Rating) []Rating) error {
+	tx, err := rr.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO ratings (account_id, rating) SELECT unnest($1::INT[]), $2 ON CONFLICT (account_id) DO NOTHING",
+		pq.Array(accountIDs),
+		initial,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(
+		ctx,
+		"SELECT account_id, rating, matches, updated_at FROM ratings WHERE account_id = ANY($1::INT[]) ORDER BY account_id FOR UPDATE",
+		pq.Array(accountIDs),
+	)
+	if err != nil {
+		return err
+	}
+
+	ratings := make([]Rating, 0, len(accountIDs))
+
+	for rows.Next() {
+		var rating Rating
+
+		if err = rows.Scan(&rating.AccountID, &rating.Rating, &rating.Matches, &rating.UpdatedAt); err != nil {
+			rows.Close()
+			return err
+		}
+
+		ratings = append(ratings, rating)
+	}
+
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	before := make(map[string]float64, len(ratings))
+	for _, rating := range ratings {
+		before[rating.AccountID] = rating.Rating
+	}
+
+	for _, rating := range rate(ratings) {
+		_, err = tx.ExecContext(
+			ctx,
+			"UPDATE ratings SET rating=$2, matches=matches+1, updated_at=CURRENT_TIMESTAMP WHERE account_id=$1",
+			rating.AccountID,
+			rating.Rating,
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO rating_history (account_id, match_id, rating_before, rating_after) VALUES ($1, $2, $3, $4)",
+			rating.AccountID,
+			matchID,
+			before[rating.AccountID],
+			rating.Rating,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
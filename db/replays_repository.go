@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type ReplaysRepository interface {
+	Save(ctx context.Context, matchID string, log []byte) (string, error)
+	GetByID(ctx context.Context, id string) (*Replay, error)
+	GetLastByMatchID(ctx context.Context, matchID string) (*Replay, error)
+	ListByMatchID(ctx context.Context, matchID string) ([]Replay, error)
+}
+
+type replaysRepository struct {
+	dbConn *sql.DB
+}
+
+type Replay struct {
+	ID        string
+	MatchID   string
+	Log       []byte
+	CreatedAt time.Time
+}
+
+func NewReplaysRepository(dbConn *sql.DB) ReplaysRepository {
+	return replaysRepository{dbConn}
+}
+
+func (rr replaysRepository) Save(ctx context.Context, matchID string, log []byte) (string, error) {
+	stmt, err := rr.dbConn.PrepareContext(ctx, "INSERT INTO replays (match_id, log) VALUES ($1, $2) RETURNING id")
+	if err != nil {
+		return "", err
+	}
+	defer stmt.Close()
+
+	var replayID string
+	err = stmt.QueryRowContext(ctx, matchID, log).Scan(&replayID)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprint(replayID), nil
+}
+
+func (rr replaysRepository) GetByID(ctx context.Context, id string) (*Replay, error) {
+	row := rr.dbConn.QueryRowContext(
+		ctx,
+		"SELECT id, match_id, log, created_at FROM replays WHERE id=$1",
+		id,
+	)
+
+	var replay Replay
+
+	err := row.Scan(&replay.ID, &replay.MatchID, &replay.Log, &replay.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &replay, nil
+}
+
+func (rr replaysRepository) GetLastByMatchID(ctx context.Context, matchID string) (*Replay, error) {
+	row := rr.dbConn.QueryRowContext(
+		ctx,
+		"SELECT id, match_id, log, created_at FROM replays WHERE match_id=$1 ORDER BY created_at DESC, id DESC LIMIT 1",
+		matchID,
+	)
+
+	var replay Replay
+
+	err := row.Scan(&replay.ID, &replay.MatchID, &replay.Log, &replay.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &replay, nil
+}
+
+// ListByMatchID lists the replays of every round of the match, the first
+// round first. Their logs are left out.
+func (rr replaysRepository) ListByMatchID(ctx context.Context, matchID string) ([]Replay, error) {
+	rows, err := rr.dbConn.QueryContext(
+		ctx,
+		"SELECT id, match_id, created_at FROM replays WHERE match_id=$1 ORDER BY created_at, id",
+		matchID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	replays := make([]Replay, 0)
+
+	for rows.Next() {
+		var replay Replay
+
+		err = rows.Scan(&replay.ID, &replay.MatchID, &replay.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		replays = append(replays, replay)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return replays, nil
+}
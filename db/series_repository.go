@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type SeriesRepository interface {
+	Save(ctx context.Context, series Series) (string, error)
+}
+
+type seriesRepository struct {
+	dbConn *sql.DB
+}
+
+// Series is a best of Length rounds that was played to the end. GameID is
+// the id of the match it was played in. A series played in teams is won by
+// WinnerTeam, otherwise by the account WinnerID.
+type Series struct {
+	ID         string
+	GameID     string
+	Length     int
+	Rounds     int
+	WinnerID   string
+	WinnerTeam string
+	EndedAt    time.Time
+	Players    []SeriesPlayer
+}
+
+// SeriesPlayer is how many rounds of a series an account won, and the team
+// it played for when the series was played in teams.
+type SeriesPlayer struct {
+	AccountID string
+	Team      string
+	Points    int
+}
+
+func NewSeriesRepository(dbConn *sql.DB) SeriesRepository {
+	return seriesRepository{dbConn}
+}
+
+func (sr seriesRepository) Save(ctx context.Context, series Series) (string, error) {
+	tx, err := sr.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var seriesID string
+
+	err = tx.QueryRowContext(
+		ctx,
+		"INSERT INTO series (game_id, length, rounds, winner_id, winner_team, ended_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id",
+		series.GameID,
+		series.Length,
+		series.Rounds,
+		nullString(series.WinnerID),
+		nullString(series.WinnerTeam),
+		series.EndedAt,
+	).Scan(&seriesID)
+	if err != nil {
+		return "", err
+	}
+
+	stmt, err := tx.PrepareContext(
+		ctx,
+		"INSERT INTO series_players (series_id, account_id, team, points) VALUES ($1, $2, $3, $4)",
+	)
+	if err != nil {
+		return "", err
+	}
+	defer stmt.Close()
+
+	for _, player := range series.Players {
+		if _, err = stmt.ExecContext(ctx, seriesID, player.AccountID, nullString(player.Team), player.Points); err != nil {
+			return "", err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprint(seriesID), nil
+}
@@ -0,0 +1,147 @@
+package game
+
+import "golang.org/x/exp/slices"
+
+// ShrinkRules make the arena shrink as a round goes on: every Interval ticks
+// the outer ring of tiles still playable turns into walls, until the arena
+// would get narrower than MinWidth or shorter than MinHeight. Warning is how
+// many ticks in advance a shrink is announced. The zero value never shrinks
+// the arena.
+type ShrinkRules struct {
+	Interval  uint64 `json:"interval,omitempty"`
+	Warning   uint64 `json:"warning,omitempty"`
+	MinWidth  int    `json:"min_width,omitempty"`
+	MinHeight int    `json:"min_height,omitempty"`
+}
+
+// Arena is the part of the map that is still playable, from the Left to the
+// Right column and from the Top to the Bottom row, all included.
+type Arena struct {
+	Left   int
+	Top    int
+	Right  int
+	Bottom int
+}
+
+// ShrinkWarning announces that the arena becomes Arena in Ticks ticks.
+type ShrinkWarning struct {
+	Arena Arena
+	Ticks uint64
+}
+
+// Enabled tells whether the rules shrink the arena at all.
+func (r ShrinkRules) Enabled() bool {
+	return r.Interval > 0
+}
+
+// rings is how many rings of tiles a map of tiles has lost at tick.
+func (r ShrinkRules) rings(tiles Tiles, tick uint64) int {
+	if !r.Enabled() {
+		return 0
+	}
+
+	limit := min(
+		(tiles.Horizontal-max(r.MinWidth, 1))/2,
+		(tiles.Vertical-max(r.MinHeight, 1))/2,
+	)
+
+	return max(min(int(tick/r.Interval), limit), 0)
+}
+
+func newArena(tiles Tiles, rings int) Arena {
+	return Arena{
+		Left:   rings,
+		Top:    rings,
+		Right:  tiles.Horizontal - 1 - rings,
+		Bottom: tiles.Vertical - 1 - rings,
+	}
+}
+
+func (a Arena) contains(bf BodyFragment) bool {
+	return bf.X >= a.Left && bf.X <= a.Right && bf.Y >= a.Top && bf.Y <= a.Bottom
+}
+
+// ring lists the tiles on the edges of the arena.
+func (a Arena) ring() []Cell {
+	cells := make([]Cell, 0)
+
+	for x := a.Left; x <= a.Right; x++ {
+		cells = append(cells, Cell{X: x, Y: a.Top})
+
+		if a.Bottom != a.Top {
+			cells = append(cells, Cell{X: x, Y: a.Bottom})
+		}
+	}
+
+	for y := a.Top + 1; y < a.Bottom; y++ {
+		cells = append(cells, Cell{X: a.Left, Y: y})
+
+		if a.Right != a.Left {
+			cells = append(cells, Cell{X: a.Right, Y: y})
+		}
+	}
+
+	return cells
+}
+
+// Arena is the part of the map still playable in the world.
+func (w World) Arena() Arena {
+	return newArena(w.Map.Tiles, w.Shrink.rings(w.Map.Tiles, w.Tick))
+}
+
+// NextShrink announces the shrink of the arena due in as many ticks as the
+// shrink rules warn in advance, if there is one.
+func (w World) NextShrink() (ShrinkWarning, bool) {
+	if !w.Shrink.Enabled() || w.Shrink.Warning == 0 {
+		return ShrinkWarning{}, false
+	}
+
+	tiles := w.Map.Tiles
+	tick := w.Tick + w.Shrink.Warning
+
+	rings := w.Shrink.rings(tiles, tick)
+	if rings == w.Shrink.rings(tiles, tick-1) {
+		return ShrinkWarning{}, false
+	}
+
+	return ShrinkWarning{
+		Arena: newArena(tiles, rings),
+		Ticks: w.Shrink.Warning,
+	}, true
+}
+
+// shrink turns the outer ring of the arena into walls when it is due, and
+// summons again the foods that were on it. Snakes whose heads are on the
+// ring die as they collide.
+func (w *World) shrink() {
+	if !w.Shrink.Enabled() || w.Tick == 0 {
+		return
+	}
+
+	tiles := w.Map.Tiles
+	rings := w.Shrink.rings(tiles, w.Tick)
+
+	if rings == w.Shrink.rings(tiles, w.Tick-1) {
+		return
+	}
+
+	// The walls may be shared with the previous worlds, so they are never
+	// appended to in place.
+	w.Map.Walls = append(slices.Clip(w.Map.Walls), newArena(tiles, rings-1).ring()...)
+
+	arena := newArena(tiles, rings)
+
+	for i, food := range w.Foods {
+		if arena.contains(BodyFragment(food)) {
+			continue
+		}
+
+		if position, ok := w.freePosition(); ok {
+			w.Foods[i] = position
+		}
+	}
+
+	w.Items = slices.DeleteFunc(w.Items, func(item Item) bool {
+		return !arena.contains(BodyFragment(item.Position))
+	})
+}
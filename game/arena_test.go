@@ -0,0 +1,102 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShrink(t *testing.T) {
+	rules := Rules{
+		Map:        Map{Tiles: Tiles{Horizontal: 10, Vertical: 8}, Edges: EdgesWall},
+		FoodsLimit: 1,
+		Shrink:     ShrinkRules{Interval: 5, Warning: 2, MinWidth: 5, MinHeight: 4},
+	}
+
+	// step runs the world until tick, with the snakes kept away from harm.
+	step := func(world World, tick uint64) World {
+		for world.Tick < tick {
+			for i := range world.Snakes {
+				world.Snakes[i].Alive = false
+			}
+
+			world, _ = Step(world, nil)
+		}
+
+		return world
+	}
+
+	t.Run("should turn the outer ring into walls on schedule", func(t *testing.T) {
+		world := NewWorld(1, rules, nil)
+
+		assert.Equal(t, Arena{Left: 0, Top: 0, Right: 9, Bottom: 7}, world.Arena())
+
+		world = step(world, 4)
+		assert.Empty(t, world.Map.Walls)
+
+		world = step(world, 5)
+		assert.Equal(t, Arena{Left: 1, Top: 1, Right: 8, Bottom: 6}, world.Arena())
+		assert.Len(t, world.Map.Walls, 2*10+2*6)
+		assert.True(t, world.Map.wallSet()[BodyFragment{X: 0, Y: 3}])
+	})
+
+	t.Run("should stop at the minimum size", func(t *testing.T) {
+		world := step(NewWorld(1, rules, nil), 30)
+
+		assert.Equal(t, Arena{Left: 2, Top: 2, Right: 7, Bottom: 5}, world.Arena())
+		assert.Len(t, world.Map.Walls, 2*10+2*6+2*8+2*4)
+	})
+
+	t.Run("should announce a shrink as many ticks as warned in advance", func(t *testing.T) {
+		world := step(NewWorld(1, rules, nil), 3)
+
+		warning, ok := world.NextShrink()
+		assert.True(t, ok)
+		assert.Equal(t, ShrinkWarning{Arena: Arena{Left: 1, Top: 1, Right: 8, Bottom: 6}, Ticks: 2}, warning)
+
+		_, ok = step(world, 4).NextShrink()
+		assert.False(t, ok)
+	})
+
+	t.Run("should summon again a food left on the walls", func(t *testing.T) {
+		world := step(NewWorld(1, rules, nil), 4)
+		world.Foods = []foodPosition{{X: 0, Y: 0}}
+
+		world = step(world, 5)
+
+		assert.True(t, world.Arena().contains(BodyFragment(world.Foods[0])))
+	})
+
+	t.Run("should kill the snake whose head ends up on the walls", func(t *testing.T) {
+		world := step(NewWorld(1, rules, []string{"1"}), 4)
+		world.Snakes[0] = Snake{
+			PlayerID: "1",
+			Body:     []BodyFragment{{X: 3, Y: 0}, {X: 2, Y: 0}, {X: 1, Y: 0}},
+			Moving:   MoveRight,
+			Alive:    true,
+		}
+
+		world, events := Step(world, nil)
+
+		assert.False(t, world.Snakes[0].Alive)
+		assert.Equal(t, []Event{{Type: EventDied, PlayerID: "1", Cause: CauseWall}}, events)
+	})
+
+	t.Run("should leave the given world untouched", func(t *testing.T) {
+		world := step(NewWorld(1, rules, nil), 4)
+
+		next := step(world, 5)
+
+		assert.Empty(t, world.Map.Walls)
+		assert.NotEmpty(t, next.Map.Walls)
+	})
+
+	t.Run("should tell the arena in the snapshots", func(t *testing.T) {
+		world := step(NewWorld(1, rules, nil), 4)
+		next := step(world, 5)
+
+		assert.Equal(t, &Arena{Left: 1, Top: 1, Right: 8, Bottom: 6}, Diff(world, next).Arena)
+		assert.Nil(t, Diff(next, step(next, 6)).Arena)
+		assert.NotNil(t, NewKeyframe(world).Arena)
+	})
+}
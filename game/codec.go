@@ -0,0 +1,33 @@
+package game
+
+import (
+	"encoding/json"
+
+	"github.com/gorilla/websocket"
+)
+
+// Codec is the wire format of a socket. Each player keeps the codec it
+// negotiated when connecting, so players of the same match can use
+// different formats.
+type Codec interface {
+	MessageType() int
+	Encode(message interface{}) ([]byte, error)
+	Decode(data []byte, message *WrittenMessage) error
+}
+
+type jsonCodec struct{}
+
+// JSONCodec is the default codec, used when no subprotocol is negotiated.
+var JSONCodec Codec = jsonCodec{}
+
+func (jsonCodec) MessageType() int {
+	return websocket.TextMessage
+}
+
+func (jsonCodec) Encode(message interface{}) ([]byte, error) {
+	return json.Marshal(message)
+}
+
+func (jsonCodec) Decode(data []byte, message *WrittenMessage) error {
+	return json.Unmarshal(data, message)
+}
@@ -0,0 +1,186 @@
+package game
+
+import "golang.org/x/exp/slices"
+
+// headOnRule tells what happens to snakes whose heads meet, either on the
+// same tile or by swapping tiles.
+type headOnRule string
+
+const (
+	HeadOnBothDie    = headOnRule("BOTH_DIE")
+	HeadOnLongerWins = headOnRule("LONGER_WINS")
+)
+
+// CollisionRules are how collisions are resolved. The zero value makes
+// every snake in a head-on collision die, kills a snake that moves into the
+// tile a tail is leaving and lets teammates through each other.
+type CollisionRules struct {
+	HeadOn       headOnRule `json:"head_on,omitempty"`
+	TailChasing  bool       `json:"tail_chasing,omitempty"`
+	FriendlyFire bool       `json:"friendly_fire,omitempty"`
+}
+
+// collide resolves the collisions of a tick after every snake has moved, so
+// the outcome does not depend on the order of the snakes: who dies is
+// decided on the world as it is after the moves and only then are the
+// deaths applied. A snake dies when its head
+//
+//   - leaves a walled map or hits a wall;
+//   - meets other heads, unless the rules let the longest snake win;
+//   - hits a body, its own included;
+//   - hits the tile a tail has just left, unless tail chasing is allowed.
+//
+// Unless friendly fire is on, the heads, bodies and tails of teammates are
+// passed through. A ghost passes through every body and tail.
+//
+// The died event tells whether the snake ran into a wall, itself or another
+// snake, which is then named as the killer.
+//
+// When boost is set, only the boosted snakes have just moved, so only their
+// tails are leaving a tile.
+func (w *World) collide(events []Event, boost bool) []Event {
+	walls := w.Map.wallSet()
+	bodies := make(map[BodyFragment][]int)
+	tails := make(map[BodyFragment][]int)
+
+	for i, snake := range w.Snakes {
+		if !snake.Alive {
+			continue
+		}
+
+		for _, bodyFragment := range snake.Body[1:] {
+			bodies[bodyFragment] = append(bodies[bodyFragment], i)
+		}
+
+		moved := snake.Control != ControlFrozen && (!boost || snake.hasEffect(ItemSpeed))
+
+		if !w.Collisions.TailChasing && moved && snake.Body[len(snake.Body)-1] != snake.LastTail {
+			tails[snake.LastTail] = append(tails[snake.LastTail], i)
+		}
+	}
+
+	// killers holds who each dying snake ran into: -1 for a wall.
+	killers := make(map[int]int)
+
+	for i, snake := range w.Snakes {
+		if !snake.Alive {
+			continue
+		}
+
+		head := snake.Body[0]
+
+		if !w.Map.Tiles.contains(head) || walls[head] {
+			killers[i] = -1
+			continue
+		}
+
+		rivals := w.headOnRivals(i)
+
+		if len(rivals) > 0 {
+			if killer, dies := w.resolveHeadOn(i, rivals); dies {
+				killers[i] = killer
+				continue
+			}
+		}
+
+		// A ghost goes through bodies and tails.
+		if snake.hasEffect(ItemGhost) {
+			continue
+		}
+
+		// A rival the snake swapped tiles with has its neck where the head
+		// of the snake is now, which was already settled as a head-on.
+		if owner, ok := w.hitBy(i, bodies[head], rivals); ok {
+			killers[i] = owner
+			continue
+		}
+
+		if owner, ok := w.hitBy(i, tails[head], nil); ok {
+			killers[i] = owner
+		}
+	}
+
+	for i := range w.Snakes {
+		killer, dies := killers[i]
+		if !dies {
+			continue
+		}
+
+		snake := &w.Snakes[i]
+		snake.Alive = false
+		snake.Movements = nil
+
+		event := Event{
+			Type:     EventDied,
+			PlayerID: snake.PlayerID,
+			Cause:    CauseSnake,
+		}
+
+		switch killer {
+		case -1:
+			event.Cause = CauseWall
+		case i:
+			event.Cause = CauseSelf
+		default:
+			event.KillerID = w.Snakes[killer].PlayerID
+		}
+
+		events = append(events, event)
+	}
+
+	return events
+}
+
+// hitBy is the first of owners the i-th snake dies hitting, leaving out its
+// teammates and the rivals of a head-on.
+func (w *World) hitBy(i int, owners []int, rivals []int) (int, bool) {
+	for _, owner := range owners {
+		if !w.teammates(i, owner) && !slices.Contains(rivals, owner) {
+			return owner, true
+		}
+	}
+
+	return 0, false
+}
+
+// headOnRivals lists the snakes whose heads met the head of the i-th snake,
+// in the order of the snakes, leaving out its teammates.
+func (w *World) headOnRivals(i int) []int {
+	snake := w.Snakes[i]
+	rivals := make([]int, 0)
+
+	for j, other := range w.Snakes {
+		if j == i || !other.Alive || w.teammates(i, j) {
+			continue
+		}
+
+		sameTile := other.Body[0] == snake.Body[0]
+		swapped := len(snake.Body) > 1 && len(other.Body) > 1 &&
+			other.Body[0] == snake.Body[1] && other.Body[1] == snake.Body[0]
+
+		if sameTile || swapped {
+			rivals = append(rivals, j)
+		}
+	}
+
+	return rivals
+}
+
+// resolveHeadOn tells whether the i-th snake dies in a head-on collision
+// with rivals and which of them killed it: the longest one, the first of them
+// on a tie.
+func (w *World) resolveHeadOn(i int, rivals []int) (int, bool) {
+	killer := rivals[0]
+
+	for _, rival := range rivals[1:] {
+		if len(w.Snakes[rival].Body) > len(w.Snakes[killer].Body) {
+			killer = rival
+		}
+	}
+
+	if w.Collisions.HeadOn == HeadOnLongerWins && len(w.Snakes[i].Body) > len(w.Snakes[killer].Body) {
+		return 0, false
+	}
+
+	return killer, true
+}
@@ -0,0 +1,232 @@
+package game
+
+import (
+	"expvar"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	sendQueueSize = 64
+	writeWait     = 10 * time.Second
+	pongWait      = 60 * time.Second
+	pingPeriod    = pongWait * 9 / 10
+)
+
+// outboundMetrics counts what was given up on to keep slow clients from
+// holding the match back. It is served with the other expvars on the
+// metrics address.
+var outboundMetrics = expvar.NewMap("outbound")
+
+type outgoing struct {
+	codec    Codec
+	data     []byte
+	snapshot bool
+	keyframe bool
+}
+
+// outbox is the bounded queue of messages waiting to be written to a socket.
+//
+// When it is full, the oldest snapshot is dropped along with the deltas that
+// follow it up to the next keyframe, since the client could not apply them
+// anyway. If no keyframe is left in the queue, the deltas that arrive later
+// are dropped too until the next keyframe. When there is no snapshot to drop,
+// push fails and the client is meant to be disconnected.
+type outbox struct {
+	queue            []outgoing
+	limit            int
+	awaitingKeyframe bool
+	dropped          int
+}
+
+func (o *outbox) push(msg outgoing) bool {
+	if msg.snapshot && !msg.keyframe && o.awaitingKeyframe {
+		o.drop(1)
+		return true
+	}
+
+	if len(o.queue) >= o.limit && !o.dropSnapshots() {
+		return false
+	}
+
+	if msg.snapshot {
+		if msg.keyframe {
+			o.awaitingKeyframe = false
+		} else if o.awaitingKeyframe {
+			o.drop(1)
+			return true
+		}
+	}
+
+	o.queue = append(o.queue, msg)
+
+	return true
+}
+
+func (o *outbox) dropSnapshots() bool {
+	start := -1
+
+	for i, msg := range o.queue {
+		if msg.snapshot {
+			start = i
+			break
+		}
+	}
+
+	if start < 0 {
+		return false
+	}
+
+	end := start + 1
+	for ; end < len(o.queue); end++ {
+		if o.queue[end].snapshot && o.queue[end].keyframe {
+			break
+		}
+	}
+
+	o.awaitingKeyframe = end == len(o.queue)
+
+	kept := o.queue[:start]
+	dropped := 0
+
+	for _, msg := range o.queue[start:end] {
+		if msg.snapshot {
+			dropped++
+		} else {
+			kept = append(kept, msg)
+		}
+	}
+
+	o.queue = append(kept, o.queue[end:]...)
+	o.drop(dropped)
+
+	return true
+}
+
+func (o *outbox) drop(n int) {
+	o.dropped += n
+	outboundMetrics.Add("dropped_snapshots", int64(n))
+}
+
+func (o *outbox) pop() (outgoing, bool) {
+	if len(o.queue) == 0 {
+		return outgoing{}, false
+	}
+
+	msg := o.queue[0]
+	o.queue[0] = outgoing{}
+	o.queue = o.queue[1:]
+
+	return msg, true
+}
+
+// connection owns the writing side of a socket. Messages are queued by the
+// match and written by a goroutine of its own, so a slow client only holds
+// itself back.
+type connection struct {
+	socket *websocket.Conn
+	codec  Codec
+	outbox outbox
+	wake   chan struct{}
+	done   chan struct{}
+	report func(err error)
+
+	sync      sync.Mutex
+	closeOnce sync.Once
+}
+
+func newConnection(socket *websocket.Conn, codec Codec, report func(err error)) *connection {
+	c := &connection{
+		socket: socket,
+		codec:  codec,
+		outbox: outbox{limit: sendQueueSize},
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+		report: report,
+	}
+
+	go c.writeLoop()
+
+	return c
+}
+
+// send queues msg without waiting for it to be written. A client too slow
+// to keep up is disconnected.
+func (c *connection) send(msg outgoing) error {
+	select {
+	case <-c.done:
+		return errConnectionClosed
+	default:
+	}
+
+	c.sync.Lock()
+	ok := c.outbox.push(msg)
+	c.sync.Unlock()
+
+	if !ok {
+		outboundMetrics.Add("evicted_clients", 1)
+		c.close()
+		return errSendQueueFull
+	}
+
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+func (c *connection) writeLoop() {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-c.wake:
+			for {
+				c.sync.Lock()
+				msg, ok := c.outbox.pop()
+				c.sync.Unlock()
+
+				if !ok {
+					break
+				}
+
+				if err := c.write(msg); err != nil {
+					c.report(err)
+					c.close()
+					return
+				}
+			}
+		case <-ticker.C:
+			err := c.socket.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait))
+			if err != nil {
+				c.report(err)
+				c.close()
+				return
+			}
+		}
+	}
+}
+
+func (c *connection) write(msg outgoing) error {
+	if err := c.socket.SetWriteDeadline(time.Now().Add(writeWait)); err != nil {
+		return err
+	}
+
+	return c.socket.WriteMessage(msg.codec.MessageType(), msg.data)
+}
+
+// close stops the writer and closes the socket, which makes the reader fail
+// and the player be disconnected.
+func (c *connection) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.socket.Close()
+	})
+}
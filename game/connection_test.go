@@ -0,0 +1,61 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutbox(t *testing.T) {
+	message := func(data string) outgoing {
+		return outgoing{codec: JSONCodec, data: []byte(data)}
+	}
+
+	snapshot := func(data string, keyframe bool) outgoing {
+		return outgoing{codec: JSONCodec, data: []byte(data), snapshot: true, keyframe: keyframe}
+	}
+
+	contents := func(o *outbox) []string {
+		data := make([]string, 0, len(o.queue))
+		for _, msg := range o.queue {
+			data = append(data, string(msg.data))
+		}
+		return data
+	}
+
+	t.Run("should drop the oldest snapshots up to the next keyframe", func(t *testing.T) {
+		o := &outbox{limit: 4}
+
+		assert.True(t, o.push(snapshot("k1", true)))
+		assert.True(t, o.push(message("m1")))
+		assert.True(t, o.push(snapshot("d1", false)))
+		assert.True(t, o.push(snapshot("k2", true)))
+		assert.True(t, o.push(snapshot("d2", false)))
+
+		assert.Equal(t, []string{"m1", "k2", "d2"}, contents(o))
+		assert.Equal(t, 2, o.dropped)
+		assert.False(t, o.awaitingKeyframe)
+	})
+
+	t.Run("should drop deltas until a keyframe when none is left", func(t *testing.T) {
+		o := &outbox{limit: 2}
+
+		assert.True(t, o.push(snapshot("d1", false)))
+		assert.True(t, o.push(snapshot("d2", false)))
+		assert.True(t, o.push(snapshot("d3", false)))
+		assert.True(t, o.push(message("m1")))
+		assert.True(t, o.push(snapshot("k1", true)))
+
+		assert.Equal(t, []string{"m1", "k1"}, contents(o))
+		assert.Equal(t, 3, o.dropped)
+		assert.False(t, o.awaitingKeyframe)
+	})
+
+	t.Run("should refuse a message when there is no snapshot to drop", func(t *testing.T) {
+		o := &outbox{limit: 1}
+
+		assert.True(t, o.push(message("m1")))
+		assert.False(t, o.push(message("m2")))
+		assert.Equal(t, []string{"m1"}, contents(o))
+	})
+}
@@ -0,0 +1,57 @@
+package game
+
+import "fmt"
+
+const errorsBufferSize = 64
+
+// Error is an error that happened inside a match or a player, away from any
+// caller that could handle it, such as while reading from a socket.
+type Error struct {
+	MatchID  string
+	PlayerID string
+	Err      error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ErrorSink delivers the errors of a match or a player. The channel is
+// never closed; errors are dropped while nobody is reading them and the
+// buffer is full, so a missing reader never stalls the game.
+type ErrorSink interface {
+	Errors() <-chan error
+}
+
+type errorSink struct {
+	errors chan error
+}
+
+func newErrorSink() *errorSink {
+	return &errorSink{
+		errors: make(chan error, errorsBufferSize),
+	}
+}
+
+func (es *errorSink) Errors() <-chan error {
+	return es.errors
+}
+
+func (es *errorSink) report(err error) {
+	select {
+	case es.errors <- err:
+	default:
+	}
+}
+
+func recoveredError(recovered interface{}) error {
+	if err, ok := recovered.(error); ok {
+		return fmt.Errorf("recovered from panic: %w", err)
+	}
+
+	return fmt.Errorf("recovered from panic: %v", recovered)
+}
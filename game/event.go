@@ -0,0 +1,32 @@
+package game
+
+type eventType string
+
+const (
+	EventAte    = eventType("ATE")
+	EventDied   = eventType("DIED")
+	EventPicked = eventType("PICKED")
+)
+
+// deathCause tells what a snake died of.
+type deathCause string
+
+const (
+	CauseSelf    = deathCause("SELF")
+	CauseSnake   = deathCause("SNAKE")
+	CauseWall    = deathCause("WALL")
+	CauseTimeout = deathCause("TIMEOUT")
+	CauseLeft    = deathCause("LEFT")
+)
+
+// Event is something that happened during a tick. Food is set on ate events,
+// Item on picked events and Cause on died events, along with KillerID when
+// the snake ran into another one.
+type Event struct {
+	Type     eventType
+	PlayerID string
+	Food     int
+	Item     itemKind
+	Cause    deathCause
+	KillerID string
+}
@@ -1,5 +1,7 @@
 package game
 
+import "sync"
+
 type FoodState interface {
 	UpdateState(input *foodStateInput)
 	OnUpdateState(fn func())
@@ -14,6 +16,8 @@ type foodPosition struct {
 type foodState struct {
 	position        foodPosition
 	onUpdateHandler func()
+
+	stateSync sync.RWMutex
 }
 
 type foodStateInput struct {
@@ -25,10 +29,14 @@ func NewFoodState() FoodState {
 }
 
 func (fs *foodState) UpdateState(input *foodStateInput) {
+	fs.stateSync.Lock()
+
 	if input.position != nil {
 		fs.position = *input.position
 	}
 
+	fs.stateSync.Unlock()
+
 	fs.dispatchUpdateEvent()
 }
 
@@ -43,5 +51,8 @@ func (fs *foodState) OnUpdateState(fn func()) {
 }
 
 func (fs *foodState) GetPosition() foodPosition {
+	fs.stateSync.RLock()
+	defer fs.stateSync.RUnlock()
+
 	return fs.position
 }
@@ -0,0 +1,232 @@
+package game
+
+import "golang.org/x/exp/slices"
+
+// itemKind tells what an item does to the snake that picks it up.
+type itemKind string
+
+const (
+	// ItemGolden grows the snake by goldenGrowth and scores a bonus.
+	ItemGolden = itemKind("GOLDEN")
+	// ItemSpeed makes the snake move two tiles a tick while it lasts.
+	ItemSpeed = itemKind("SPEED")
+	// ItemGhost lets the snake through bodies while it lasts.
+	ItemGhost = itemKind("GHOST")
+	// ItemShrink takes shrinkLength fragments off the snake.
+	ItemShrink = itemKind("SHRINK")
+	// ItemReverse turns the moves of the snake around while it lasts.
+	ItemReverse = itemKind("REVERSE")
+)
+
+// itemKinds are the kinds of items, in the order their spawn weights are
+// drawn from.
+var itemKinds = []itemKind{ItemGolden, ItemSpeed, ItemGhost, ItemShrink, ItemReverse}
+
+const (
+	goldenGrowth = 3
+	goldenBonus  = 5
+	shrinkLength = 3
+	minLength    = 2
+)
+
+// ItemKindRules tell how often a kind of item spawns compared to the others,
+// how many ticks it stays on the map and how many ticks its effect lasts.
+type ItemKindRules struct {
+	Weight   int    `json:"weight"`
+	Lifetime uint64 `json:"lifetime"`
+	Duration uint64 `json:"duration,omitempty"`
+}
+
+// ItemRules are how items spawn: one every Interval ticks while there are
+// fewer than Limit on the map, of a kind drawn by weight. The zero value
+// never spawns an item.
+type ItemRules struct {
+	Limit    int                        `json:"limit,omitempty"`
+	Interval uint64                     `json:"interval,omitempty"`
+	Kinds    map[itemKind]ItemKindRules `json:"kinds,omitempty"`
+}
+
+// DefaultItemRules spawn up to limit items of every kind, one every 5
+// seconds, staying 10 seconds on the map with effects that last 5 seconds.
+func DefaultItemRules(limit int, tickRate int) ItemRules {
+	seconds := func(n int) uint64 {
+		return uint64(n * tickRate)
+	}
+
+	return ItemRules{
+		Limit:    limit,
+		Interval: seconds(5),
+		Kinds: map[itemKind]ItemKindRules{
+			ItemGolden:  {Weight: 30, Lifetime: seconds(10)},
+			ItemSpeed:   {Weight: 20, Lifetime: seconds(10), Duration: seconds(5)},
+			ItemGhost:   {Weight: 15, Lifetime: seconds(10), Duration: seconds(5)},
+			ItemShrink:  {Weight: 20, Lifetime: seconds(10)},
+			ItemReverse: {Weight: 15, Lifetime: seconds(10), Duration: seconds(5)},
+		},
+	}
+}
+
+func (r ItemRules) enabled() bool {
+	return r.Limit > 0 && r.Interval > 0
+}
+
+// Item is a special food lying on the map until the tick it expires at.
+type Item struct {
+	ID        uint64
+	Kind      itemKind
+	Position  Cell
+	ExpiresAt uint64
+}
+
+// Effect is an item acting on a snake until the tick it lasts to.
+type Effect struct {
+	Kind  itemKind
+	Until uint64
+}
+
+func (s Snake) hasEffect(kind itemKind) bool {
+	for _, effect := range s.Effects {
+		if effect.Kind == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// expireEffects drops the effects that lasted to the previous tick.
+func (w *World) expireEffects() {
+	for i := range w.Snakes {
+		snake := &w.Snakes[i]
+
+		snake.Effects = slices.DeleteFunc(snake.Effects, func(effect Effect) bool {
+			return effect.Until < w.Tick
+		})
+	}
+}
+
+// pickItems applies the items under a head to the snake and takes them off
+// the map.
+func (w *World) pickItems(events []Event) []Event {
+	items := make([]Item, 0, len(w.Items))
+
+	for _, item := range w.Items {
+		picked := false
+
+		for j := range w.Snakes {
+			snake := &w.Snakes[j]
+
+			if !snake.Alive || snake.Body[0] != BodyFragment(item.Position) {
+				continue
+			}
+
+			w.apply(snake, item.Kind)
+			picked = true
+
+			events = append(events, Event{
+				Type:     EventPicked,
+				PlayerID: snake.PlayerID,
+				Item:     item.Kind,
+			})
+
+			break
+		}
+
+		if !picked {
+			items = append(items, item)
+		}
+	}
+
+	w.Items = items
+
+	return events
+}
+
+// apply gives the snake what an item of kind does. A timed effect picked up
+// again lasts for its whole duration from then on.
+func (w *World) apply(snake *Snake, kind itemKind) {
+	switch kind {
+	case ItemGolden:
+		snake.ToIncrease += goldenGrowth
+	case ItemShrink:
+		length := max(len(snake.Body)-shrinkLength, minLength)
+		snake.Body = slices.Clone(snake.Body[:min(length, len(snake.Body))])
+		snake.ToIncrease = 0
+	default:
+		until := w.Tick + w.ItemRules.Kinds[kind].Duration
+
+		snake.Effects = slices.DeleteFunc(snake.Effects, func(effect Effect) bool {
+			return effect.Kind == kind
+		})
+		snake.Effects = append(snake.Effects, Effect{Kind: kind, Until: until})
+	}
+}
+
+// spawnItems takes the expired items off the map and, when one is due,
+// spawns an item of a kind drawn by weight on a free tile.
+func (w *World) spawnItems() {
+	if !w.ItemRules.enabled() {
+		return
+	}
+
+	w.Items = slices.DeleteFunc(w.Items, func(item Item) bool {
+		return item.ExpiresAt <= w.Tick
+	})
+
+	if w.Tick%w.ItemRules.Interval != 0 || len(w.Items) >= w.ItemRules.Limit {
+		return
+	}
+
+	kind, ok := w.drawItemKind()
+	if !ok {
+		return
+	}
+
+	position, ok := w.freePosition()
+	if !ok {
+		return
+	}
+
+	w.nextItem++
+
+	w.Items = append(w.Items, Item{
+		ID:        w.nextItem,
+		Kind:      kind,
+		Position:  Cell(position),
+		ExpiresAt: w.Tick + w.ItemRules.Kinds[kind].Lifetime,
+	})
+}
+
+func (w *World) drawItemKind() (itemKind, bool) {
+	total := 0
+	for _, kind := range itemKinds {
+		total += max(w.ItemRules.Kinds[kind].Weight, 0)
+	}
+
+	if total == 0 {
+		return "", false
+	}
+
+	n := w.Rand.Intn(total)
+
+	for _, kind := range itemKinds {
+		n -= max(w.ItemRules.Kinds[kind].Weight, 0)
+
+		if n < 0 {
+			return kind, true
+		}
+	}
+
+	return "", false
+}
+
+// boosting tells whether a snake alive moves faster.
+func (w World) boosting() bool {
+	for _, snake := range w.Snakes {
+		if snake.Alive && snake.hasEffect(ItemSpeed) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,203 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItems(t *testing.T) {
+	// newWorld has a single snake heading right, its head next to an item
+	// of kind.
+	newWorld := func(kind itemKind) World {
+		return World{
+			Map:       Map{Tiles: Tiles{Horizontal: 20, Vertical: 10}},
+			ItemRules: DefaultItemRules(1, 1),
+			Rand:      NewRand(1),
+			Snakes: []Snake{{
+				PlayerID: "1",
+				Body:     []BodyFragment{{X: 5, Y: 5}, {X: 4, Y: 5}, {X: 3, Y: 5}, {X: 2, Y: 5}, {X: 1, Y: 5}},
+				Moving:   MoveRight,
+				Alive:    true,
+			}},
+			Items: []Item{{ID: 1, Kind: kind, Position: Cell{X: 6, Y: 5}, ExpiresAt: 10}},
+		}
+	}
+
+	t.Run("should grow the snake and score a bonus on a golden item", func(t *testing.T) {
+		world := newWorld(ItemGolden)
+		scoreboard := NewScoreboard(world)
+
+		world, events := Step(world, nil)
+		scoreboard = scoreboard.Record(world, events)
+
+		assert.Empty(t, world.Items)
+		assert.Contains(t, events, Event{Type: EventPicked, PlayerID: "1", Item: ItemGolden})
+		assert.Equal(t, goldenBonus, scoreboard[0].Bonus)
+
+		for i := 0; i < goldenGrowth; i++ {
+			world, _ = Step(world, nil)
+		}
+
+		assert.Len(t, world.Snakes[0].Body, 5+goldenGrowth)
+	})
+
+	t.Run("should shrink the snake, keeping its head", func(t *testing.T) {
+		world, _ := Step(newWorld(ItemShrink), nil)
+
+		assert.Equal(t, []BodyFragment{{X: 6, Y: 5}, {X: 5, Y: 5}}, world.Snakes[0].Body)
+
+		world.Items = []Item{{ID: 2, Kind: ItemShrink, Position: Cell{X: 7, Y: 5}, ExpiresAt: 10}}
+		world, _ = Step(world, nil)
+
+		assert.Len(t, world.Snakes[0].Body, minLength)
+	})
+
+	t.Run("should move a boosted snake two tiles a tick until the boost is over", func(t *testing.T) {
+		world, _ := Step(newWorld(ItemSpeed), nil)
+
+		assert.Equal(t, []Effect{{Kind: ItemSpeed, Until: 6}}, world.Snakes[0].Effects)
+		assert.Equal(t, BodyFragment{X: 7, Y: 5}, world.Snakes[0].Body[0])
+		assert.Len(t, world.Snakes[0].Body, 5)
+
+		for world.Tick < 6 {
+			world, _ = Step(world, nil)
+		}
+
+		assert.Equal(t, BodyFragment{X: 17, Y: 5}, world.Snakes[0].Body[0])
+
+		world, _ = Step(world, nil)
+		assert.Empty(t, world.Snakes[0].Effects)
+		assert.Equal(t, BodyFragment{X: 18, Y: 5}, world.Snakes[0].Body[0])
+	})
+
+	t.Run("should let a boosted snake follow the tail of a snake that moved once", func(t *testing.T) {
+		world := newWorld(ItemGolden)
+		world.Items = nil
+		world.Snakes = []Snake{
+			{
+				PlayerID: "1",
+				Body:     []BodyFragment{{X: 1, Y: 5}, {X: 0, Y: 5}},
+				Moving:   MoveRight,
+				Alive:    true,
+				Effects:  []Effect{{Kind: ItemSpeed, Until: 10}},
+			},
+			{
+				PlayerID: "2",
+				Body:     []BodyFragment{{X: 5, Y: 5}, {X: 4, Y: 5}, {X: 3, Y: 5}},
+				Moving:   MoveRight,
+				Alive:    true,
+			},
+		}
+
+		world, events := Step(world, nil)
+
+		assert.Empty(t, eventsOf(events, EventDied))
+		assert.Equal(t, BodyFragment{X: 3, Y: 5}, world.Snakes[0].Body[0])
+		assert.True(t, world.Snakes[0].Alive)
+	})
+
+	t.Run("should let a ghost through bodies but not through walls", func(t *testing.T) {
+		world := newWorld(ItemGhost)
+		world.Snakes = append(world.Snakes, Snake{
+			PlayerID: "2",
+			Body:     []BodyFragment{{X: 7, Y: 2}, {X: 7, Y: 3}, {X: 7, Y: 4}, {X: 7, Y: 5}, {X: 7, Y: 6}},
+			Moving:   MoveUp,
+			Alive:    true,
+		})
+
+		world, events := Step(world, nil)
+		world, events = Step(world, nil)
+
+		assert.True(t, world.Snakes[0].Alive)
+		assert.Empty(t, eventsOf(events, EventDied))
+
+		world.Map.Walls = []Cell{{X: 8, Y: 5}}
+		world, _ = Step(world, nil)
+
+		assert.False(t, world.Snakes[0].Alive)
+	})
+
+	t.Run("should turn the moves of a reversed snake around", func(t *testing.T) {
+		world, _ := Step(newWorld(ItemReverse), nil)
+		world, _ = Step(world, []Input{{PlayerID: "1", Move: MoveUp}})
+
+		assert.Equal(t, MoveDown, world.Snakes[0].Moving)
+		assert.Equal(t, BodyFragment{X: 6, Y: 6}, world.Snakes[0].Body[0])
+	})
+
+	t.Run("should drop an item once it expires", func(t *testing.T) {
+		world := newWorld(ItemGolden)
+		world.Items[0].Position = Cell{X: 0, Y: 0}
+		world.ItemRules.Interval = 100
+
+		for world.Tick < 9 {
+			world, _ = Step(world, nil)
+		}
+
+		assert.Len(t, world.Items, 1)
+
+		world, _ = Step(world, nil)
+
+		assert.Empty(t, world.Items)
+	})
+
+	t.Run("should spawn items on schedule up to the limit", func(t *testing.T) {
+		world := newWorld(ItemGolden)
+		world.Items = nil
+		world.ItemRules.Limit = 2
+
+		for world.Tick < 4 {
+			world, _ = Step(world, nil)
+		}
+
+		assert.Empty(t, world.Items)
+
+		world, _ = Step(world, nil)
+		assert.Len(t, world.Items, 1)
+		assert.Equal(t, uint64(5+10), world.Items[0].ExpiresAt)
+
+		for world.Tick < 15 {
+			world, _ = Step(world, nil)
+		}
+
+		assert.Len(t, world.Items, 2)
+		assert.NotEqual(t, world.Items[0].ID, world.Items[1].ID)
+	})
+
+	t.Run("should spawn the same items from the same seed", func(t *testing.T) {
+		run := func() []Item {
+			world := newWorld(ItemGolden)
+			world.Items = nil
+
+			for world.Tick < 20 {
+				world, _ = Step(world, nil)
+			}
+
+			return world.Items
+		}
+
+		assert.Equal(t, run(), run())
+	})
+
+	t.Run("should leave the effects of the given world untouched", func(t *testing.T) {
+		world, _ := Step(newWorld(ItemSpeed), nil)
+		world.Items = []Item{{ID: 2, Kind: ItemGhost, Position: Cell{X: 8, Y: 5}, ExpiresAt: 10}}
+
+		Step(world, nil)
+
+		assert.Equal(t, []Effect{{Kind: ItemSpeed, Until: 6}}, world.Snakes[0].Effects)
+	})
+}
+
+func eventsOf(events []Event, eventType eventType) []Event {
+	found := make([]Event, 0)
+
+	for _, event := range events {
+		if event.Type == eventType {
+			found = append(found, event)
+		}
+	}
+
+	return found
+}
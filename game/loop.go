@@ -0,0 +1,368 @@
+package game
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Maycon-Santos/go-snake-backend/utils"
+)
+
+const inputsBufferSize = 64
+
+var errMatchClosed = errors.New("match: the match is closed")
+
+type inputKind int
+
+const (
+	inputJoin inputKind = iota
+	inputWatch
+	inputLeave
+	inputMove
+	inputReady
+	inputUnready
+	inputDisconnect
+	inputResume
+	inputExpire
+	inputAssignTeam
+	inputBalanceTeams
+)
+
+type input struct {
+	kind   inputKind
+	player Player
+	move   movement
+	seq    uint64
+	target string
+	team   string
+	result chan error
+}
+
+// send delivers the input to the match loop and waits until it is applied.
+func (m *match) send(in input) error {
+	in.result = make(chan error, 1)
+
+	select {
+	case m.inputs <- in:
+	case <-m.done:
+		return errMatchClosed
+	}
+
+	select {
+	case err := <-in.result:
+		return err
+	case <-m.done:
+		return errMatchClosed
+	}
+}
+
+// dispatch delivers the input to the match loop without waiting for it.
+func (m *match) dispatch(in input) {
+	select {
+	case m.inputs <- in:
+	case <-m.done:
+	}
+}
+
+// loop is the only goroutine allowed to change the state of a match.
+//
+// Once every player is ready the match counts down, one second at a time,
+// before the round starts. Someone joining or getting unready during the
+// countdown sends the match back to the lobby.
+//
+// Every change comes in through the inputs channel. While a round is being
+// played, inputs are held until the beginning of the next tick, so the
+// outcome of a tick depends only on the inputs received since the previous
+// one. Each tick goes through the following phases, always in this order:
+//
+//  1. input:     pending joins, leaves, disconnections, moves and ready
+//     changes are applied
+//  2. move:      every alive snake advances one tile
+//  3. wrap:      heads that left the map reappear on the opposite edge,
+//     unless its edges are walls
+//  4. eat:       foods under a head are eaten and summoned again, and items
+//     under a head are picked up and take effect
+//  5. grow:      snakes that have eaten grow by their last tail
+//  6. shrink:    when the arena shrinks, its outer ring turns into walls, the
+//     foods on it are summoned again and the items on it are dropped
+//  7. collide:   snakes whose head hit a body, a wall, another head or a
+//     leaving tail, or left a walled map, die as the collision rules say;
+//     ghosts go through bodies and tails
+//  8. boost:     snakes with a speed boost move, wrap, eat, grow and collide
+//     once more
+//  9. items:     expired items are dropped and, when one is due, a new item
+//     is spawned
+//  10. broadcast: state changes made during the tick are dispatched, along
+//     with the scoreboard when a score changed
+//
+// When the players are split in teams, the round ends as soon as a single
+// team has snakes alive. In a shrinking arena it ends as soon as a single
+// snake is left alive.
+//
+// When the time limit is reached, the round goes into sudden death for the
+// sudden death duration, if there is one, and ends as soon as at most one
+// snake is left alive. Otherwise, or once the sudden death is over too, the
+// snakes still alive die of timeout before the broadcast and the round ends.
+// The results are then shown for the results duration before the match goes
+// back to the lobby.
+//
+// Spectators do not take part in the simulation, so they are let in as soon
+// as they arrive.
+//
+// A panic while applying an input or running a tick closes the match and is
+// delivered to its error sink, leaving the other matches running.
+func (m *match) loop() {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			m.report(&Error{
+				MatchID: m.ID,
+				Err:     recoveredError(recovered),
+			})
+
+			m.stopTimers()
+			m.Close()
+		}
+	}()
+
+	for {
+		var tick, countdown, results <-chan time.Time
+		if m.ticker != nil {
+			tick = m.ticker.C
+		}
+
+		if m.countdown != nil {
+			countdown = m.countdown.C
+		}
+
+		if m.results != nil {
+			results = m.results.C
+		}
+
+		select {
+		case <-m.done:
+			m.stopTimers()
+			return
+		case in := <-m.inputs:
+			m.receive(in)
+		case <-tick:
+			m.tick()
+		case <-countdown:
+			m.countDown()
+		case <-results:
+			m.results = nil
+			m.backToLobby()
+		}
+	}
+}
+
+// receive applies in at once, or holds it for the next tick while a round
+// is being played. A move that arrives outside a round is dropped.
+func (m *match) receive(in input) {
+	if in.kind == inputWatch {
+		m.apply(in)
+		return
+	}
+
+	if m.GetStatus().IsPlaying() {
+		m.pending = append(m.pending, in)
+		return
+	}
+
+	if in.kind == inputMove {
+		return
+	}
+
+	m.apply(in)
+}
+
+func (m *match) stopTimers() {
+	if m.ticker != nil {
+		m.ticker.Stop()
+	}
+
+	if m.countdown != nil {
+		m.countdown.Stop()
+	}
+
+	if m.results != nil {
+		m.results.Stop()
+	}
+}
+
+func (m *match) apply(in input) {
+	var err error
+
+	switch in.kind {
+	case inputJoin:
+		if err = m.join(in.player); err == nil {
+			m.joinTeam(in.player)
+			m.dispatchJoin(in.player)
+			m.cancelCountdown()
+		}
+	case inputWatch:
+		err = m.watch(in.player)
+	case inputLeave:
+		m.remove(in.player)
+	case inputReady:
+		m.ready(in.player)
+	case inputUnready:
+		m.unready(in.player)
+	case inputDisconnect:
+		m.disconnect(in.player)
+	case inputResume:
+		err = m.resume(in.player)
+	case inputExpire:
+		m.expire(in.player, in.seq)
+	case inputAssignTeam:
+		err = m.assignTeam(in.player, in.target, in.team)
+	case inputBalanceTeams:
+		err = m.balanceTeams(in.player)
+	}
+
+	if in.result != nil {
+		in.result <- err
+	}
+}
+
+func (m *match) tick() {
+	pending := m.pending
+	m.pending = nil
+
+	inputs := make([]Input, 0, len(pending))
+
+	for _, in := range pending {
+		if in.kind == inputMove {
+			if in.player.IsSpectator() {
+				continue
+			}
+
+			inputs = append(inputs, Input{
+				PlayerID: in.player.GetID(),
+				Move:     in.move,
+			})
+
+			continue
+		}
+
+		m.apply(in)
+	}
+
+	inputs = append(inputs, m.controls...)
+	m.controls = nil
+
+	prev := m.world
+
+	var events []Event
+	m.world, events = Step(m.world, inputs)
+	m.recorder.record(m.world.Tick, inputs)
+
+	if m.world.HasAliveSnakes() && m.timeIsUp() && !m.startSuddenDeath() {
+		var timeoutEvents []Event
+		m.world, timeoutEvents = TimeOut(m.world)
+		events = append(events, timeoutEvents...)
+	}
+
+	m.broadcast(prev)
+
+	if len(events) > 0 {
+		m.scoreboard = m.scoreboard.Record(m.world, events)
+		m.dispatchScoreboard()
+	}
+
+	if m.roundOver() {
+		m.end()
+	}
+}
+
+// roundOver tells whether the round ends with the tick just run.
+func (m *match) roundOver() bool {
+	alive := m.world.AliveSnakes()
+
+	switch {
+	case alive == 0, m.world.LastTeamStanding():
+		return true
+	case alive == 1 && m.GetStatus() == StatusSuddenDeath:
+		return true
+	case alive == 1 && m.world.Shrink.Enabled() && len(m.world.Snakes) > 1:
+		return true
+	}
+
+	return false
+}
+
+// timeIsUp tells whether the time limit, or the sudden death that followed
+// it, is over.
+func (m *match) timeIsUp() bool {
+	if m.GetStatus() == StatusSuddenDeath {
+		return m.world.Tick >= m.suddenDeathEnd
+	}
+
+	limit := m.GetTimeLimit()
+	if limit <= 0 {
+		return false
+	}
+
+	return m.world.Tick >= m.ticks(limit)
+}
+
+// startSuddenDeath puts off the end of a round whose time is up for the
+// sudden death duration, telling whether it did.
+func (m *match) startSuddenDeath() bool {
+	suddenDeath := m.GetSuddenDeath()
+	if m.GetStatus() != StatusRunning || suddenDeath <= 0 {
+		return false
+	}
+
+	m.suddenDeathEnd = m.world.Tick + m.ticks(suddenDeath)
+
+	return m.setStatus(StatusSuddenDeath)
+}
+
+// ticks is how many ticks the match runs in d.
+func (m *match) ticks(d time.Duration) uint64 {
+	return uint64(d.Seconds() * float64(m.GetTickRate()))
+}
+
+// broadcast copies the outcome of a tick from the world to the players and
+// foods, and dispatches a snapshot of what changed.
+func (m *match) broadcast(prev World) {
+	for _, player := range m.GetPlayers() {
+		snake, ok := m.world.GetSnake(player.GetID())
+		if !ok {
+			continue
+		}
+
+		player.setState(PlayerStateInput{
+			IsAlive: utils.Ptr(snake.Alive),
+			Body:    snake.Body,
+			Effects: append(make([]Effect, 0, len(snake.Effects)), snake.Effects...),
+		})
+	}
+
+	for i, food := range m.GetFoods() {
+		if i < len(m.world.Foods) {
+			food.UpdateState(&foodStateInput{
+				position: &m.world.Foods[i],
+			})
+		}
+	}
+
+	m.dispatchSnapshot(Diff(prev, m.world))
+}
+
+func (m *match) dispatchScoreboard() {
+	m.onScoreboardSync.Lock()
+	defer m.onScoreboardSync.Unlock()
+
+	for _, fn := range m.onScoreboardHandlers {
+		fn(m.scoreboard)
+	}
+}
+
+func (m *match) dispatchSnapshot(snapshot Snapshot) {
+	m.onSnapshotSync.Lock()
+	defer m.onSnapshotSync.Unlock()
+
+	for _, fn := range m.onSnapshotHandlers {
+		fn(snapshot)
+	}
+}
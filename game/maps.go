@@ -0,0 +1,292 @@
+package game
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed maps
+var mapFiles embed.FS
+
+var (
+	ErrSpawnBlocked     = errors.New("map: there is no room for every snake to spawn")
+	ErrSpawnUnreachable = errors.New("map: the spawn points are not connected")
+	ErrNoRoomForFood    = errors.New("map: there is no room for food")
+	ErrSpawnCount       = errors.New("map: the number of snakes to spawn is negative")
+	ErrSpawnLength      = errors.New("map: the snakes to spawn are shorter than one fragment")
+)
+
+// maps holds the named map definitions shipped with the binary. They are
+// read once, when the package is loaded, so a broken definition is caught as
+// soon as the server starts.
+var maps = mustLoadMaps(mapFiles, "maps")
+
+// GetMapDefinition returns the named map definition.
+func GetMapDefinition(name string) (Map, bool) {
+	_map, ok := maps[name]
+
+	return _map, ok
+}
+
+// MapNames lists the names of the map definitions, sorted.
+func MapNames() []string {
+	names := make([]string, 0, len(maps))
+
+	for name := range maps {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func mustLoadMaps(files embed.FS, dir string) map[string]Map {
+	entries, err := files.ReadDir(dir)
+	if err != nil {
+		panic(err)
+	}
+
+	loaded := make(map[string]Map, len(entries))
+
+	for _, entry := range entries {
+		data, err := files.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+
+		ext := path.Ext(entry.Name())
+		name := strings.TrimSuffix(entry.Name(), ext)
+
+		var _map Map
+
+		switch ext {
+		case ".json":
+			_map, err = parseJSONMap(data)
+		case ".txt":
+			_map, err = parseASCIIMap(data)
+		default:
+			err = fmt.Errorf("unknown map format %q", ext)
+		}
+
+		if err != nil {
+			panic(fmt.Errorf("maps: %s: %w", entry.Name(), err))
+		}
+
+		_map.Name = name
+		loaded[name] = _map
+	}
+
+	return loaded
+}
+
+// parseJSONMap reads a map written as the JSON encoding of Map.
+func parseJSONMap(data []byte) (Map, error) {
+	var _map Map
+
+	if err := json.Unmarshal(data, &_map); err != nil {
+		return Map{}, err
+	}
+
+	return _map, validateMap(_map)
+}
+
+// parseASCIIMap reads a map drawn as a grid, as described on NewMapFromGrid.
+// The grid may be preceded by "key: value" lines; "edges" takes "wrap" or
+// "wall".
+func parseASCIIMap(data []byte) (Map, error) {
+	edges := EdgesWrap
+	rows := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		if len(rows) == 0 {
+			if key, value, ok := strings.Cut(line, ":"); ok {
+				switch strings.TrimSpace(key) {
+				case "edges":
+					edges = edgePolicy(strings.ToUpper(strings.TrimSpace(value)))
+				default:
+					return Map{}, fmt.Errorf("unknown option %q", key)
+				}
+
+				continue
+			}
+		}
+
+		if line != "" {
+			rows = append(rows, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return Map{}, err
+	}
+
+	return NewMapFromGrid(edges, rows)
+}
+
+// NewMapFromGrid builds a map drawn as a grid, one row per string, where '#'
+// is a wall and '.' is a free tile.
+func NewMapFromGrid(edges edgePolicy, rows []string) (Map, error) {
+	_map := Map{Edges: edges}
+
+	for y, row := range rows {
+		if y == 0 {
+			_map.Tiles.Horizontal = len(row)
+		} else if len(row) != _map.Tiles.Horizontal {
+			return Map{}, fmt.Errorf("row %d has %d tiles, expected %d", y, len(row), _map.Tiles.Horizontal)
+		}
+
+		for x, tile := range row {
+			switch tile {
+			case '#':
+				_map.Walls = append(_map.Walls, Cell{X: x, Y: y})
+			case '.':
+			default:
+				return Map{}, fmt.Errorf("unknown tile %q at %d,%d", tile, x, y)
+			}
+		}
+	}
+
+	_map.Tiles.Vertical = len(rows)
+
+	return _map, validateMap(_map)
+}
+
+// Grid draws the map as NewMapFromGrid reads it.
+func (m Map) Grid() []string {
+	walls := m.wallSet()
+	rows := make([]string, 0, m.Tiles.Vertical)
+
+	for y := 0; y < m.Tiles.Vertical; y++ {
+		row := make([]byte, 0, m.Tiles.Horizontal)
+
+		for x := 0; x < m.Tiles.Horizontal; x++ {
+			if walls[BodyFragment{X: x, Y: y}] {
+				row = append(row, '#')
+			} else {
+				row = append(row, '.')
+			}
+		}
+
+		rows = append(rows, string(row))
+	}
+
+	return rows
+}
+
+func validateMap(_map Map) error {
+	if _map.Tiles.Horizontal <= 0 || _map.Tiles.Vertical <= 0 {
+		return fmt.Errorf("the map has no tiles")
+	}
+
+	if _map.Edges != EdgesWrap && _map.Edges != EdgesWall {
+		return fmt.Errorf("unknown edges %q", _map.Edges)
+	}
+
+	for _, wall := range _map.Walls {
+		if !_map.Tiles.contains(BodyFragment(wall)) {
+			return fmt.Errorf("the wall at %d,%d is out of the map", wall.X, wall.Y)
+		}
+	}
+
+	return nil
+}
+
+// wallSet indexes the walls of the map by the tile they take.
+func (m Map) wallSet() map[BodyFragment]bool {
+	walls := make(map[BodyFragment]bool, len(m.Walls))
+
+	for _, wall := range m.Walls {
+		walls[BodyFragment(wall)] = true
+	}
+
+	return walls
+}
+
+// ValidateMap checks that a round of playersLimit snakes of initialLength
+// fragments can be played on the map: AllocateSpawns finds a spawn for every
+// snake, all of them can reach each other and there is room left for food
+// where they can get to it.
+func ValidateMap(_map Map, playersLimit, initialLength int) error {
+	if err := validateMap(_map); err != nil {
+		return err
+	}
+
+	spawns, err := AllocateSpawns(_map, playersLimit, initialLength)
+	if err != nil {
+		return err
+	}
+
+	if len(spawns) == 0 {
+		return nil
+	}
+
+	occupied := make(map[BodyFragment]bool)
+	for _, spawn := range spawns {
+		for _, bodyFragment := range spawn.Body {
+			occupied[bodyFragment] = true
+		}
+	}
+
+	reachable := _map.reachable(spawns[0].Body[0])
+
+	for n, spawn := range spawns {
+		if !reachable[spawn.Body[0]] {
+			return fmt.Errorf("%w: spawn %d", ErrSpawnUnreachable, n+1)
+		}
+	}
+
+	for tile := range reachable {
+		if !occupied[tile] {
+			return nil
+		}
+	}
+
+	return ErrNoRoomForFood
+}
+
+// neighbor is the tile next to bf towards mv, wrapping around the edges
+// unless they are walls.
+func (m Map) neighbor(bf BodyFragment, mv movement) (BodyFragment, bool) {
+	next := bf.next(mv)
+
+	if m.Edges == EdgesWall {
+		return next, m.Tiles.contains(next)
+	}
+
+	return m.Tiles.wrap(next), true
+}
+
+// reachable lists the free tiles a snake at start can get to.
+func (m Map) reachable(start BodyFragment) map[BodyFragment]bool {
+	walls := m.wallSet()
+	reached := map[BodyFragment]bool{start: true}
+	queue := []BodyFragment{start}
+
+	for len(queue) > 0 {
+		tile := queue[0]
+		queue = queue[1:]
+
+		for _, mv := range []movement{MoveUp, MoveDown, MoveLeft, MoveRight} {
+			next, ok := m.neighbor(tile, mv)
+			if !ok || walls[next] || reached[next] {
+				continue
+			}
+
+			reached[next] = true
+			queue = append(queue, next)
+		}
+	}
+
+	return reached
+}
@@ -0,0 +1,90 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaps(t *testing.T) {
+	t.Run("should load every map shipped with the server", func(t *testing.T) {
+		assert.Equal(t, []string{"arena", "box", "pillars"}, MapNames())
+
+		arena, ok := GetMapDefinition("arena")
+		assert.True(t, ok)
+		assert.Equal(t, "arena", arena.Name)
+		assert.Equal(t, EdgesWall, arena.Edges)
+		assert.Equal(t, Tiles{Horizontal: 64, Vertical: 36}, arena.Tiles)
+		assert.Len(t, arena.Walls, 2*64+2*34)
+	})
+
+	t.Run("should read a map drawn as a grid", func(t *testing.T) {
+		_map, err := parseASCIIMap([]byte("edges: wall\n#..\n..#\n"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, Map{
+			Tiles: Tiles{Horizontal: 3, Vertical: 2},
+			Edges: EdgesWall,
+			Walls: []Cell{{X: 0, Y: 0}, {X: 2, Y: 1}},
+		}, _map)
+	})
+
+	t.Run("should refuse a grid with rows of different lengths", func(t *testing.T) {
+		_, err := parseASCIIMap([]byte("...\n..\n"))
+
+		assert.EqualError(t, err, "row 1 has 2 tiles, expected 3")
+	})
+
+	t.Run("should refuse a wall out of the map", func(t *testing.T) {
+		_, err := parseJSONMap([]byte(`{"tiles": {"horizontal": 2, "vertical": 2}, "edges": "WRAP", "walls": [{"x": 2, "y": 0}]}`))
+
+		assert.EqualError(t, err, "the wall at 2,0 is out of the map")
+	})
+
+	t.Run("should have room for the longest snakes of a full match on every map", func(t *testing.T) {
+		for _, name := range MapNames() {
+			_map, _ := GetMapDefinition(name)
+
+			assert.NoError(t, ValidateMap(_map, 9, 8), name)
+		}
+	})
+	t.Run("should draw a map as the grid it was read from", func(t *testing.T) {
+		rows := []string{"#..", "..#"}
+
+		_map, err := NewMapFromGrid(EdgesWrap, rows)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rows, _map.Grid())
+	})
+}
+
+func TestValidateMap(t *testing.T) {
+	open := func(width, height int) []string {
+		rows := make([]string, height)
+
+		for y := range rows {
+			rows[y] = strings.Repeat(".", width)
+		}
+
+		return rows
+	}
+
+	t.Run("should accept an open map", func(t *testing.T) {
+		_map, _ := NewMapFromGrid(EdgesWall, open(32, 18))
+
+		assert.NoError(t, ValidateMap(_map, 9, 3))
+	})
+
+	t.Run("should refuse a map without room for every snake", func(t *testing.T) {
+		rows := make([]string, 18)
+		for y := range rows {
+			rows[y] = strings.Repeat("#", 32)
+		}
+		rows[5] = "#.....#" + strings.Repeat("#", 25)
+
+		_map, _ := NewMapFromGrid(EdgesWall, rows)
+
+		assert.ErrorIs(t, ValidateMap(_map, 1, 3), ErrSpawnBlocked)
+	})
+}
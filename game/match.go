@@ -1,10 +1,13 @@
 package game
 
 import (
+	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/Maycon-Santos/go-snake-backend/utils"
+	"golang.org/x/exp/slices"
 )
 
 type Lobby struct {
@@ -15,59 +18,176 @@ type Message struct {
 	Lobby Lobby `json:"lobby,omitempty"`
 }
 
+var (
+	errPlayerNotInMatch = errors.New("match: the player is not in the match")
+	errNoTeams          = errors.New("match: the players are not split in teams")
+	errNotOwner         = errors.New("match: only the owner can change the teams")
+	errNotInLobby       = errors.New("match: the teams can only be changed in the lobby")
+	errNotInRoster      = errors.New("match: the match is reserved to other players")
+)
+
 type Match interface {
-	SendMessage(message []byte) error
+	SendMessage(message interface{}) error
+	SendSnapshot(message interface{}, keyframe bool) error
 	GetID() string
 	GetOwner() Player
+	GetPlayersLimit() int
 	GetPlayers() []Player
 	GetPlayerByID(id string) *Player
+	GetSpectators() []Player
+	GetSpectatorByID(id string) *Player
 	GetFoods() []Food
 	Enter(player Player) error
+	Watch(spectator Player) error
 	RemovePlayer(player Player)
+	Disconnect(player Player)
+	Resume(player Player) error
+	Move(player Player, mv movement)
+	Ready(player Player)
+	Unready(player Player)
+	AssignTeam(owner Player, playerID string, team string)
+	BalanceTeams(owner Player)
 	OnStart(fn func())
-	Ready()
-	Unready()
+	OnCountdown(fn func(remaining int))
+	OnJoin(fn func(player Player))
+	OnLeave(fn func(player Player))
+	OnEnd(fn func(result Result, replay Replay))
+	OnSnapshot(fn func(snapshot Snapshot))
+	OnScoreboard(fn func(scoreboard Scoreboard))
+	Close()
+	Done() <-chan struct{}
+	ErrorSink
 	MatchState
 }
 
 type match struct {
-	ID           string
-	playersLimit int
+	ID              string
+	playersLimit    int
+	spectatorsLimit int
+
+	owner      Player
+	players    []Player
+	spectators []Player
+	foods      []Food
+
+	world      World
+	scoreboard Scoreboard
+	series     Series
+	startedAt  time.Time
+	recorder   *recorder
+	inputs     chan input
+	pending    []input
+	controls   []Input
+	ticker     *time.Ticker
+	done       chan struct{}
+
+	countdown      *time.Ticker
+	remaining      int
+	results        *time.Timer
+	suddenDeathEnd uint64
+
+	disconnected   map[Player]disconnection
+	disconnections uint64
+
+	onStartHandlers      []func()
+	onCountdownHandlers  []func(remaining int)
+	onJoinHandlers       []func(player Player)
+	onLeaveHandlers      []func(player Player)
+	onEndHandlers        []func(result Result, replay Replay)
+	onSnapshotHandlers   []func(snapshot Snapshot)
+	onScoreboardHandlers []func(scoreboard Scoreboard)
+
+	onStartSync      sync.Mutex
+	onCountdownSync  sync.Mutex
+	onJoinSync       sync.Mutex
+	onLeaveSync      sync.Mutex
+	onEndSync        sync.Mutex
+	onSnapshotSync   sync.Mutex
+	onScoreboardSync sync.Mutex
+	sync             sync.RWMutex
+	closeOnce        sync.Once
+
+	*errorSink
+	MatchState
+}
 
-	owner        Player
-	players      []Player
-	foods        []Food
-	playersReady int
+// disconnection is a player waiting to reconnect. seq tells apart the
+// disconnections of the same player, so a timer that fires after the player
+// came back and dropped again does not cut the new grace short.
+type disconnection struct {
+	seq   uint64
+	timer *time.Timer
+}
 
-	ticker          GameTicker
-	onStartHandlers []func()
+func NewMatch(id string, playersLimit, spectatorsLimit int) Match {
+	m := newMatch(id, playersLimit, spectatorsLimit)
 
-	onStartSync sync.Mutex
-	foodsSync   sync.Mutex
+	go m.loop()
 
-	MatchState
+	return m
 }
 
-func NewMatch(id string, playersLimit int) Match {
+// newMatch builds a match whose loop is not running yet.
+func newMatch(id string, playersLimit, spectatorsLimit int) *match {
 	return &match{
-		ID:           id,
-		playersLimit: playersLimit,
-		players:      []Player{},
-		ticker:       NewTicker(),
-		MatchState:   NewMatchState(),
+		ID:              id,
+		playersLimit:    playersLimit,
+		spectatorsLimit: spectatorsLimit,
+		players:         []Player{},
+		spectators:      []Player{},
+		inputs:          make(chan input, inputsBufferSize),
+		done:            make(chan struct{}),
+		disconnected:    make(map[Player]disconnection),
+		errorSink:       newErrorSink(),
+		MatchState:      NewMatchState(),
 	}
 }
 
-func (m *match) SendMessage(message []byte) (err error) {
-	for _, player := range m.GetPlayers() {
-		err = player.SendMessage(message)
-		if err != nil {
-			// Enviar erros para um chan
-			continue
+func (m *match) SendMessage(message interface{}) error {
+	return m.broadcastEncoded(message, func(player Player, codec Codec, data []byte) error {
+		return player.SendEncoded(codec, data)
+	})
+}
+
+// SendSnapshot sends a message carrying a snapshot, which players that fall
+// behind may drop as described on outbox.
+func (m *match) SendSnapshot(message interface{}, keyframe bool) error {
+	return m.broadcastEncoded(message, func(player Player, codec Codec, data []byte) error {
+		return player.SendSnapshot(codec, data, keyframe)
+	})
+}
+
+// broadcastEncoded encodes message once per codec and sends it to every
+// player and spectator. Failing to reach one of them does not stop the
+// others; those errors go to the error sink of the match.
+func (m *match) broadcastEncoded(message interface{}, send func(player Player, codec Codec, data []byte) error) error {
+	encoded := make(map[Codec][]byte)
+
+	for _, player := range append(m.GetPlayers(), m.GetSpectators()...) {
+		codec := player.GetCodec()
+
+		data, ok := encoded[codec]
+		if !ok {
+			var err error
+
+			data, err = codec.Encode(message)
+			if err != nil {
+				return err
+			}
+
+			encoded[codec] = data
+		}
+
+		if err := send(player, codec, data); err != nil && err != errSocketNotSet && err != errConnectionClosed {
+			m.report(&Error{
+				MatchID:  m.ID,
+				PlayerID: player.GetID(),
+				Err:      err,
+			})
 		}
 	}
 
-	return
+	return nil
 }
 
 func (m *match) playersLen() int {
@@ -78,16 +198,29 @@ func (m *match) GetID() string {
 	return m.ID
 }
 
+func (m *match) GetPlayersLimit() int {
+	return m.playersLimit
+}
+
 func (m *match) GetOwner() Player {
+	m.sync.RLock()
+	defer m.sync.RUnlock()
+
 	return m.owner
 }
 
 func (m *match) GetPlayers() []Player {
+	m.sync.RLock()
+	defer m.sync.RUnlock()
+
 	if m.owner == nil {
 		return nil
 	}
 
-	return append(m.players, m.owner)
+	players := make([]Player, 0, m.playersLen())
+	players = append(players, m.players...)
+
+	return append(players, m.owner)
 }
 
 func (m *match) GetPlayerByID(id string) *Player {
@@ -99,9 +232,27 @@ func (m *match) GetPlayerByID(id string) *Player {
 	return nil
 }
 
+func (m *match) GetSpectators() []Player {
+	m.sync.RLock()
+	defer m.sync.RUnlock()
+
+	spectators := make([]Player, 0, len(m.spectators))
+
+	return append(spectators, m.spectators...)
+}
+
+func (m *match) GetSpectatorByID(id string) *Player {
+	for _, spectator := range m.GetSpectators() {
+		if spectator.GetID() == id {
+			return &spectator
+		}
+	}
+	return nil
+}
+
 func (m *match) GetFoods() []Food {
-	m.foodsSync.Lock()
-	defer m.foodsSync.Unlock()
+	m.sync.RLock()
+	defer m.sync.RUnlock()
 
 	return m.foods
 }
@@ -109,6 +260,138 @@ func (m *match) GetFoods() []Food {
 func (m *match) Enter(player Player) error {
 	player.SetMatch(m)
 
+	return m.send(input{kind: inputJoin, player: player})
+}
+
+func (m *match) Watch(spectator Player) error {
+	spectator.SetMatch(m)
+
+	return m.send(input{kind: inputWatch, player: spectator})
+}
+
+func (m *match) RemovePlayer(player Player) {
+	m.send(input{kind: inputLeave, player: player})
+}
+
+// Disconnect keeps the place of a player that lost the connection for the
+// reconnect grace. While the match is running its snake is steered as set by
+// the reconnect mode. The player is removed if it does not resume in time.
+func (m *match) Disconnect(player Player) {
+	m.dispatch(input{kind: inputDisconnect, player: player})
+}
+
+// Resume gives the control back to a player that reconnected within the
+// grace.
+func (m *match) Resume(player Player) error {
+	return m.send(input{kind: inputResume, player: player})
+}
+
+func (m *match) Move(player Player, mv movement) {
+	m.dispatch(input{kind: inputMove, player: player, move: mv})
+}
+
+func (m *match) Ready(player Player) {
+	m.dispatch(input{kind: inputReady, player: player})
+}
+
+func (m *match) Unready(player Player) {
+	m.dispatch(input{kind: inputUnready, player: player})
+}
+
+// AssignTeam moves a player to team, when asked by the owner in the lobby of
+// a match split in teams.
+func (m *match) AssignTeam(owner Player, playerID string, team string) {
+	m.dispatch(input{kind: inputAssignTeam, player: owner, target: playerID, team: team})
+}
+
+// BalanceTeams spreads the players evenly over the teams, when asked by the
+// owner in the lobby of a match split in teams.
+func (m *match) BalanceTeams(owner Player) {
+	m.dispatch(input{kind: inputBalanceTeams, player: owner})
+}
+
+func (m *match) OnStart(fn func()) {
+	m.onStartSync.Lock()
+	defer m.onStartSync.Unlock()
+
+	m.onStartHandlers = append(m.onStartHandlers, fn)
+}
+
+// OnCountdown registers fn to be called every second of the countdown before
+// a round, with the seconds remaining.
+func (m *match) OnCountdown(fn func(remaining int)) {
+	m.onCountdownSync.Lock()
+	defer m.onCountdownSync.Unlock()
+
+	m.onCountdownHandlers = append(m.onCountdownHandlers, fn)
+}
+
+// OnJoin registers fn to be called whenever a player takes a place in the
+// match.
+func (m *match) OnJoin(fn func(player Player)) {
+	m.onJoinSync.Lock()
+	defer m.onJoinSync.Unlock()
+
+	m.onJoinHandlers = append(m.onJoinHandlers, fn)
+}
+
+// OnLeave registers fn to be called whenever a player or spectator is removed
+// from the match, be it on purpose or because its reconnect grace expired.
+func (m *match) OnLeave(fn func(player Player)) {
+	m.onLeaveSync.Lock()
+	defer m.onLeaveSync.Unlock()
+
+	m.onLeaveHandlers = append(m.onLeaveHandlers, fn)
+}
+
+// OnEnd registers fn to be called when a round is over, with the final
+// scores, the series standings and the replay of the round.
+func (m *match) OnEnd(fn func(result Result, replay Replay)) {
+	m.onEndSync.Lock()
+	defer m.onEndSync.Unlock()
+
+	m.onEndHandlers = append(m.onEndHandlers, fn)
+}
+
+func (m *match) OnSnapshot(fn func(snapshot Snapshot)) {
+	m.onSnapshotSync.Lock()
+	defer m.onSnapshotSync.Unlock()
+
+	m.onSnapshotHandlers = append(m.onSnapshotHandlers, fn)
+}
+
+// OnScoreboard registers fn to be called when a round starts and whenever a
+// score changes during it.
+func (m *match) OnScoreboard(fn func(scoreboard Scoreboard)) {
+	m.onScoreboardSync.Lock()
+	defer m.onScoreboardSync.Unlock()
+
+	m.onScoreboardHandlers = append(m.onScoreboardHandlers, fn)
+}
+
+func (m *match) Close() {
+	m.closeOnce.Do(func() {
+		m.UpdateState(MatchStateInput{
+			Status: utils.Ptr(StatusClosed),
+		})
+
+		close(m.done)
+	})
+}
+
+// Done is closed once the match is closed.
+func (m *match) Done() <-chan struct{} {
+	return m.done
+}
+
+func (m *match) join(player Player) error {
+	if !m.Admits(player.GetID()) {
+		return errNotInRoster
+	}
+
+	m.sync.Lock()
+	defer m.sync.Unlock()
+
 	if m.owner == nil {
 		m.owner = player
 	} else if m.playersLen() < int(m.playersLimit) {
@@ -120,7 +403,42 @@ func (m *match) Enter(player Player) error {
 	return nil
 }
 
-func (m *match) RemovePlayer(player Player) {
+func (m *match) dispatchJoin(player Player) {
+	m.onJoinSync.Lock()
+	for _, fn := range m.onJoinHandlers {
+		fn(player)
+	}
+	m.onJoinSync.Unlock()
+}
+
+func (m *match) watch(spectator Player) error {
+	m.sync.Lock()
+	defer m.sync.Unlock()
+
+	if len(m.spectators) >= m.spectatorsLimit {
+		return fmt.Errorf("The match already has the maximum number of spectators (%d)", len(m.spectators))
+	}
+
+	m.spectators = append(m.spectators, spectator)
+
+	return nil
+}
+
+func (m *match) leave(player Player) {
+	m.sync.Lock()
+	defer m.sync.Unlock()
+
+	if player.IsSpectator() {
+		for i, s := range m.spectators {
+			if player == s {
+				m.spectators = append(m.spectators[:i], m.spectators[i+1:]...)
+				break
+			}
+		}
+
+		return
+	}
+
 	if m.owner == player {
 		m.owner = nil
 
@@ -139,101 +457,452 @@ func (m *match) RemovePlayer(player Player) {
 	}
 }
 
-func (m *match) Unready() {
-	m.playersReady -= 1
+func (m *match) contains(player Player) bool {
+	m.sync.RLock()
+	defer m.sync.RUnlock()
 
-	if m.playersReady < 0 {
-		m.playersReady = 0
+	if player == m.owner {
+		return true
 	}
+
+	for _, p := range append(m.players, m.spectators...) {
+		if player == p {
+			return true
+		}
+	}
+
+	return false
 }
 
-func (m *match) Ready() {
-	m.playersReady += 1
+func (m *match) remove(player Player) {
+	if d, ok := m.disconnected[player]; ok {
+		d.timer.Stop()
+		delete(m.disconnected, player)
+	}
 
-	if m.playersReady == len(m.GetPlayers()) {
-		m.UpdateState(MatchStateInput{
-			Status: utils.Ptr(StatusRunning),
+	if !m.contains(player) {
+		return
+	}
+
+	m.leave(player)
+
+	if !player.IsSpectator() {
+		m.control(player, ControlLeft)
+	}
+
+	if len(m.GetPlayers()) == 0 {
+		m.cancelCountdown()
+	}
+
+	m.onLeaveSync.Lock()
+	for _, fn := range m.onLeaveHandlers {
+		fn(player)
+	}
+	m.onLeaveSync.Unlock()
+}
+
+func (m *match) disconnect(player Player) {
+	if player.IsSpectator() {
+		m.remove(player)
+		return
+	}
+
+	if _, ok := m.disconnected[player]; ok || !m.contains(player) {
+		return
+	}
+
+	m.disconnections++
+	seq := m.disconnections
+
+	m.disconnected[player] = disconnection{
+		seq: seq,
+		timer: time.AfterFunc(m.GetReconnectGrace(), func() {
+			m.dispatch(input{kind: inputExpire, player: player, seq: seq})
+		}),
+	}
+
+	player.UpdateState(PlayerStateInput{
+		IsConnected: utils.Ptr(false),
+	})
+
+	if mode := m.GetReconnectMode(); mode != ControlPlayer {
+		m.control(player, mode)
+	}
+}
+
+func (m *match) resume(player Player) error {
+	d, ok := m.disconnected[player]
+	if !ok {
+		if !m.contains(player) {
+			return errPlayerNotInMatch
+		}
+
+		return nil
+	}
+
+	d.timer.Stop()
+	delete(m.disconnected, player)
+
+	player.UpdateState(PlayerStateInput{
+		IsConnected: utils.Ptr(true),
+	})
+
+	if m.GetReconnectMode() != ControlPlayer {
+		m.control(player, ControlPlayer)
+	}
+
+	return nil
+}
+
+func (m *match) expire(player Player, seq uint64) {
+	if d, ok := m.disconnected[player]; ok && d.seq == seq {
+		m.remove(player)
+	}
+}
+
+// setStatus moves the match to status, reporting an illegal transition to
+// the error sink of the match. Close may close the match from another
+// goroutine while the loop is still running a tick or a countdown, so a
+// match found closed is left as it is without reporting anything.
+func (m *match) setStatus(status matchStatus) bool {
+	err := m.UpdateState(MatchStateInput{
+		Status: &status,
+	})
+
+	if err != nil {
+		if m.GetStatus() == StatusClosed {
+			return false
+		}
+
+		m.report(&Error{
+			MatchID: m.ID,
+			Err:     err,
 		})
 
+		return false
+	}
+
+	return true
+}
+
+// control queues a change of who is steering the snake of player. It only
+// has effect while a round is being played, and is applied on the next tick.
+func (m *match) control(player Player, c control) {
+	if !m.GetStatus().IsPlaying() {
+		return
+	}
+
+	m.controls = append(m.controls, Input{
+		PlayerID: player.GetID(),
+		Control:  &c,
+	})
+}
+
+func (m *match) ready(player Player) {
+	if m.GetStatus() != StatusLobby || player.IsSpectator() {
+		return
+	}
+
+	player.UpdateState(PlayerStateInput{
+		IsReady: utils.Ptr(true),
+	})
+
+	for _, p := range m.GetPlayers() {
+		if !p.IsReady() {
+			return
+		}
+	}
+
+	m.startCountdown()
+}
+
+func (m *match) unready(player Player) {
+	status := m.GetStatus()
+	if (status != StatusLobby && status != StatusCountdown) || player.IsSpectator() {
+		return
+	}
+
+	player.UpdateState(PlayerStateInput{
+		IsReady: utils.Ptr(false),
+	})
+
+	m.cancelCountdown()
+}
+
+// startCountdown moves the match to the countdown, which the loop counts
+// down every second until the round starts.
+func (m *match) startCountdown() {
+	if !m.setStatus(StatusCountdown) {
+		return
+	}
+
+	countdown := m.GetCountdown()
+	if countdown <= 0 {
 		m.start()
+		return
 	}
+
+	m.remaining = int((countdown + time.Second - 1) / time.Second)
+	m.countdown = time.NewTicker(time.Second)
+	m.dispatchCountdown()
 }
 
-func (m *match) OnStart(fn func()) {
-	m.onStartSync.Lock()
-	defer m.onStartSync.Unlock()
+// countDown is called by the loop every second of the countdown.
+func (m *match) countDown() {
+	m.remaining--
 
-	m.onStartHandlers = append(m.onStartHandlers, fn)
+	if m.remaining > 0 {
+		m.dispatchCountdown()
+		return
+	}
+
+	m.stopCountdown()
+	m.start()
+}
+
+// cancelCountdown sends the match back to the lobby when the countdown is
+// going on.
+func (m *match) cancelCountdown() {
+	if m.GetStatus() != StatusCountdown {
+		return
+	}
+
+	m.stopCountdown()
+	m.setStatus(StatusLobby)
+}
+
+func (m *match) stopCountdown() {
+	if m.countdown != nil {
+		m.countdown.Stop()
+		m.countdown = nil
+	}
+
+	m.remaining = 0
+}
+
+func (m *match) dispatchCountdown() {
+	m.onCountdownSync.Lock()
+	defer m.onCountdownSync.Unlock()
+
+	for _, fn := range m.onCountdownHandlers {
+		fn(m.remaining)
+	}
+}
+
+// joinTeam puts a player that just joined a match split in teams on the
+// team with the fewest players.
+func (m *match) joinTeam(player Player) {
+	teams := TeamIDs(m.GetTeams())
+	if len(teams) == 0 || player.IsSpectator() {
+		return
+	}
+
+	others := make([]Player, 0)
+	for _, p := range m.GetPlayers() {
+		if p != player {
+			others = append(others, p)
+		}
+	}
+
+	team := smallestTeam(others, teams)
+
+	player.UpdateState(PlayerStateInput{
+		Team: &team,
+	})
+}
+
+// teamsEditableBy tells why the teams cannot be changed by player, if they
+// cannot.
+func (m *match) teamsEditableBy(player Player) error {
+	if m.GetTeams() == 0 {
+		return errNoTeams
+	}
+
+	if player != m.GetOwner() {
+		return errNotOwner
+	}
+
+	if m.GetStatus() != StatusLobby {
+		return errNotInLobby
+	}
+
+	return nil
+}
+
+func (m *match) assignTeam(owner Player, playerID string, team string) error {
+	if err := m.teamsEditableBy(owner); err != nil {
+		return err
+	}
+
+	if !slices.Contains(TeamIDs(m.GetTeams()), team) {
+		return fmt.Errorf("match: there is no team %s", team)
+	}
+
+	player := m.GetPlayerByID(playerID)
+	if player == nil {
+		return errPlayerNotInMatch
+	}
+
+	(*player).UpdateState(PlayerStateInput{
+		Team: &team,
+	})
+
+	return nil
+}
+
+func (m *match) balanceTeams(owner Player) error {
+	if err := m.teamsEditableBy(owner); err != nil {
+		return err
+	}
+
+	for player, team := range balanceTeams(m.GetPlayers(), TeamIDs(m.GetTeams())) {
+		player.UpdateState(PlayerStateInput{
+			Team: utils.Ptr(team),
+		})
+	}
+
+	return nil
 }
 
 func (m *match) start() {
-	m.ticker.Reset()
+	players := m.GetPlayers()
+	playerIDs := make([]string, 0, len(players))
 
-	m.foodsSync.Lock()
-	m.foods = make([]Food, 0, m.GetFoodsLimit())
-	m.foodsSync.Unlock()
+	for _, player := range players {
+		playerIDs = append(playerIDs, player.GetID())
+	}
 
-	for i := 0; i < m.GetFoodsLimit(); i++ {
-		food := NewFood()
-		food.SetMatch(m)
-		m.foods = append(m.foods, food)
+	rules := Rules{
+		Map:           m.GetMap(),
+		FoodsLimit:    m.GetFoodsLimit(),
+		InitialLength: m.GetInitialLength(),
+		Collisions:    m.GetCollisionRules(),
+		Shrink:        m.GetShrinkRules(),
+		Items:         m.GetItemRules(),
 	}
 
+	if m.GetTeams() > 0 {
+		rules.Teams = make(map[string]string, len(players))
+
+		for _, player := range players {
+			rules.Teams[player.GetID()] = player.GetTeam()
+		}
+	}
+
+	if m.series.Length == 0 {
+		m.series = NewSeries(m.GetSeriesLength())
+	}
+
+	seed := time.Now().UnixNano()
+	m.world = NewWorld(seed, rules, playerIDs)
+	m.recorder = newRecorder(seed, rules, m.GetTickRate(), m.ticks(m.GetTimeLimit()), m.ticks(m.GetSuddenDeath()), players)
+	m.controls = nil
+
+	foods := make([]Food, 0, len(m.world.Foods))
+	for range m.world.Foods {
+		foods = append(foods, NewFood())
+	}
+
+	m.sync.Lock()
+	m.foods = foods
+	m.sync.Unlock()
+
 	m.onStartSync.Lock()
 	for _, fn := range m.onStartHandlers {
 		fn()
 	}
 	m.onStartSync.Unlock()
 
-	for i, player := range m.GetPlayers() {
-		player := player
-
-		player.OnDie(func() {
-			for _, p := range m.GetPlayers() {
-				if p.IsAlive() {
-					return
-				}
-			}
-
-			m.end()
-		})
+	for _, player := range players {
+		snake, _ := m.world.GetSnake(player.GetID())
 
 		player.UpdateState(PlayerStateInput{
 			IsReady: utils.Ptr(false),
-			IsAlive: utils.Ptr(true),
+			IsAlive: utils.Ptr(snake.Alive),
+			Body:    snake.Body,
+			Effects: []Effect{},
 		})
+	}
 
-		player.GenerateInitialBody(i)
+	for i, food := range foods {
+		food.UpdateState(&foodStateInput{
+			position: &m.world.Foods[i],
+		})
+	}
 
-		m.playersReady = 0
+	m.setStatus(StatusRunning)
 
-		m.ticker.OnTick(func() {
-			player.OpenBatch()
-			player.Move()
-			player.TeleportCornerScreen()
-		}, 0)
+	m.dispatchSnapshot(NewKeyframe(m.world))
 
-		m.ticker.OnTick(func() {
-			player.Increase()
-			player.DieOnPlayerCollision()
-			player.CloseBatch()
-		}, 2)
-	}
+	m.scoreboard = NewScoreboard(m.world)
+	m.startedAt = time.Now()
+	m.dispatchScoreboard()
 
-	for _, food := range m.foods {
-		food.Summon()
-		m.ticker.OnTick(food.CheckWasEaten, 1)
+	if mode := m.GetReconnectMode(); mode != ControlPlayer {
+		for player := range m.disconnected {
+			m.control(player, mode)
+		}
 	}
+
+	m.suddenDeathEnd = 0
+	m.ticker = time.NewTicker(time.Second / time.Duration(m.GetTickRate()))
 }
 
+// end stops the round and shows its results for the results duration,
+// after which the match returns to the lobby.
 func (m *match) end() {
-	m.UpdateState(MatchStateInput{
-		Status: utils.Ptr(StatusOnHold),
-	})
+	m.ticker.Stop()
+	m.ticker = nil
+	m.controls = nil
+
+	m.setStatus(StatusResults)
 
+	m.sync.Lock()
 	m.foods = make([]Food, 0)
+	m.sync.Unlock()
 
 	for _, player := range m.GetPlayers() {
 		player.Reset()
 	}
+
+	scoreboard := m.scoreboard.Finish()
+	m.series = m.series.Record(scoreboard)
+
+	result := Result{
+		Ticks:      m.world.Tick,
+		StartedAt:  m.startedAt,
+		EndedAt:    time.Now(),
+		Scoreboard: scoreboard,
+		Series:     m.series,
+	}
+
+	replay := m.recorder.replay
+	m.recorder = nil
+	m.scoreboard = nil
+
+	m.onEndSync.Lock()
+	for _, fn := range m.onEndHandlers {
+		fn(result, replay)
+	}
+	m.onEndSync.Unlock()
+
+	if _, over := m.series.Winner(); over {
+		m.series = Series{}
+	}
+
+	if duration := m.GetResultsDuration(); duration > 0 {
+		m.results = time.NewTimer(duration)
+		return
+	}
+
+	m.backToLobby()
+}
+
+func (m *match) backToLobby() {
+	if m.results != nil {
+		m.results.Stop()
+		m.results = nil
+	}
+
+	m.setStatus(StatusLobby)
 }
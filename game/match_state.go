@@ -1,69 +1,285 @@
 package game
 
-import "sync"
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
 
 type matchStatus string
 
+// A match goes through its statuses as follows, and may be closed at any of
+// them:
+//
+//	LOBBY -> COUNTDOWN -> RUNNING -> [SUDDEN_DEATH] -> RESULTS -> LOBBY
+//
+// A countdown is interrupted back to the lobby when someone is no longer
+// ready.
 const (
-	StatusOnHold  = matchStatus("ON_HOLD")
-	StatusRunning = matchStatus("RUNNING")
+	StatusLobby       = matchStatus("LOBBY")
+	StatusCountdown   = matchStatus("COUNTDOWN")
+	StatusRunning     = matchStatus("RUNNING")
+	StatusSuddenDeath = matchStatus("SUDDEN_DEATH")
+	StatusResults     = matchStatus("RESULTS")
+	StatusClosed      = matchStatus("CLOSED")
 )
 
+var Statuses = []matchStatus{
+	StatusLobby,
+	StatusCountdown,
+	StatusRunning,
+	StatusSuddenDeath,
+	StatusResults,
+	StatusClosed,
+}
+
+var ErrIllegalTransition = errors.New("match: illegal status transition")
+
+var transitions = map[matchStatus][]matchStatus{
+	StatusLobby:       {StatusCountdown, StatusClosed},
+	StatusCountdown:   {StatusLobby, StatusRunning, StatusClosed},
+	StatusRunning:     {StatusSuddenDeath, StatusResults, StatusClosed},
+	StatusSuddenDeath: {StatusResults, StatusClosed},
+	StatusResults:     {StatusLobby, StatusClosed},
+}
+
+// CanBecome tells whether a match in the status s may move to next.
+func (s matchStatus) CanBecome(next matchStatus) bool {
+	for _, status := range transitions[s] {
+		if status == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsPlaying tells whether a round is being simulated in the status s.
+func (s matchStatus) IsPlaying() bool {
+	return s == StatusRunning || s == StatusSuddenDeath
+}
+
+// edgePolicy tells what happens to a snake that leaves the map.
+type edgePolicy string
+
+const (
+	EdgesWrap = edgePolicy("WRAP")
+	EdgesWall = edgePolicy("WALL")
+)
+
+const defaultTickRate = 18
+
 type MatchState interface {
-	UpdateState(input MatchStateInput)
+	UpdateState(input MatchStateInput) error
 	OnUpdateState(fn func())
 	GetMap() Map
 	GetFoodsLimit() int
+	GetTickRate() int
+	GetInitialLength() int
+	GetTimeLimit() time.Duration
+	GetCountdown() time.Duration
+	GetSuddenDeath() time.Duration
+	GetResultsDuration() time.Duration
+	GetSeriesLength() int
+	GetTeams() int
+	GetShrinkRules() ShrinkRules
+	GetItemRules() ItemRules
+	GetCollisionRules() CollisionRules
 	GetStatus() matchStatus
+	GetReconnectGrace() time.Duration
+	GetReconnectMode() control
+	GetMode() string
+	IsPublic() bool
+	Admits(playerID string) bool
 }
 
 type Tiles struct {
-	Horizontal int
-	Vertical   int
+	Horizontal int `json:"horizontal"`
+	Vertical   int `json:"vertical"`
+}
+
+// Cell is a tile of the map.
+type Cell struct {
+	X int `json:"x"`
+	Y int `json:"y"`
 }
 
 type Map struct {
-	Tiles Tiles
+	Name  string     `json:"name,omitempty"`
+	Tiles Tiles      `json:"tiles"`
+	Edges edgePolicy `json:"edges,omitempty"`
+	Walls []Cell     `json:"walls,omitempty"`
 }
 
 type matchState struct {
 	status           matchStatus
 	_map             Map
 	foodsLimit       int
+	tickRate         int
+	initialLength    int
+	timeLimit        time.Duration
+	countdown        time.Duration
+	suddenDeath      time.Duration
+	resultsDuration  time.Duration
+	seriesLength     int
+	teams            int
+	shrink           ShrinkRules
+	items            ItemRules
+	collisions       CollisionRules
+	reconnectGrace   time.Duration
+	reconnectMode    control
+	mode             string
+	public           bool
+	roster           []string
 	onUpdateHandlers []func()
 	sync             sync.Mutex
+	stateSync        sync.RWMutex
 }
 
 type MapInput struct {
+	Name  *string
 	Tiles *Tiles
+	Edges *edgePolicy
+	Walls []Cell
 }
 
 type MatchStateInput struct {
-	Status     *matchStatus
-	Map        *MapInput
-	FoodsLimit *int
+	Status          *matchStatus
+	Map             *MapInput
+	FoodsLimit      *int
+	TickRate        *int
+	InitialLength   *int
+	TimeLimit       *time.Duration
+	Countdown       *time.Duration
+	SuddenDeath     *time.Duration
+	ResultsDuration *time.Duration
+	SeriesLength    *int
+	Teams           *int
+	Shrink          *ShrinkRules
+	Items           *ItemRules
+	Collisions      *CollisionRules
+	ReconnectGrace  *time.Duration
+	ReconnectMode   *control
+	Mode            *string
+	Public          *bool
+	Roster          []string
 }
 
 func NewMatchState() MatchState {
-	return &matchState{}
+	return &matchState{
+		status: StatusLobby,
+	}
 }
 
-func (ms *matchState) UpdateState(input MatchStateInput) {
+// UpdateState applies every field set in input. A status change must be one
+// of the legal transitions; otherwise nothing is applied and
+// ErrIllegalTransition is returned.
+func (ms *matchState) UpdateState(input MatchStateInput) error {
+	ms.stateSync.Lock()
+
 	if input.Status != nil {
+		if !ms.status.CanBecome(*input.Status) {
+			ms.stateSync.Unlock()
+			return fmt.Errorf("%w: from %s to %s", ErrIllegalTransition, ms.status, *input.Status)
+		}
+
 		ms.status = *input.Status
 	}
 
 	if input.Map != nil {
+		if input.Map.Name != nil {
+			ms._map.Name = *input.Map.Name
+		}
+
 		if input.Map.Tiles != nil {
 			ms._map.Tiles = *input.Map.Tiles
 		}
+
+		if input.Map.Walls != nil {
+			ms._map.Walls = input.Map.Walls
+		}
+
+		if input.Map.Edges != nil {
+			ms._map.Edges = *input.Map.Edges
+		}
 	}
 
 	if input.FoodsLimit != nil {
 		ms.foodsLimit = *input.FoodsLimit
 	}
 
+	if input.TickRate != nil {
+		ms.tickRate = *input.TickRate
+	}
+
+	if input.InitialLength != nil {
+		ms.initialLength = *input.InitialLength
+	}
+
+	if input.TimeLimit != nil {
+		ms.timeLimit = *input.TimeLimit
+	}
+
+	if input.Countdown != nil {
+		ms.countdown = *input.Countdown
+	}
+
+	if input.SuddenDeath != nil {
+		ms.suddenDeath = *input.SuddenDeath
+	}
+
+	if input.ResultsDuration != nil {
+		ms.resultsDuration = *input.ResultsDuration
+	}
+
+	if input.SeriesLength != nil {
+		ms.seriesLength = *input.SeriesLength
+	}
+
+	if input.Teams != nil {
+		ms.teams = *input.Teams
+	}
+
+	if input.Shrink != nil {
+		ms.shrink = *input.Shrink
+	}
+
+	if input.Items != nil {
+		ms.items = *input.Items
+	}
+
+	if input.Collisions != nil {
+		ms.collisions = *input.Collisions
+	}
+
+	if input.ReconnectGrace != nil {
+		ms.reconnectGrace = *input.ReconnectGrace
+	}
+
+	if input.ReconnectMode != nil {
+		ms.reconnectMode = *input.ReconnectMode
+	}
+
+	if input.Mode != nil {
+		ms.mode = *input.Mode
+	}
+
+	if input.Public != nil {
+		ms.public = *input.Public
+	}
+
+	if input.Roster != nil {
+		ms.roster = input.Roster
+	}
+
+	ms.stateSync.Unlock()
+
 	ms.dispatchUpdateEvent()
+
+	return nil
 }
 
 func (ms *matchState) dispatchUpdateEvent() {
@@ -85,14 +301,176 @@ func (ms *matchState) OnUpdateState(fn func()) {
 }
 
 func (ms *matchState) GetMap() Map {
+	ms.stateSync.RLock()
+	defer ms.stateSync.RUnlock()
+
 	return ms._map
 }
 
 func (ms *matchState) GetFoodsLimit() int {
+	ms.stateSync.RLock()
+	defer ms.stateSync.RUnlock()
+
 	return ms.foodsLimit
 }
 
+// GetTickRate is how many ticks the match runs per second, 18 unless set.
+func (ms *matchState) GetTickRate() int {
+	ms.stateSync.RLock()
+	defer ms.stateSync.RUnlock()
+
+	if ms.tickRate <= 0 {
+		return defaultTickRate
+	}
+
+	return ms.tickRate
+}
+
+// GetInitialLength is how many fragments the snakes have when a round
+// starts, 3 unless set.
+func (ms *matchState) GetInitialLength() int {
+	ms.stateSync.RLock()
+	defer ms.stateSync.RUnlock()
+
+	if ms.initialLength <= 0 {
+		return defaultInitialLength
+	}
+
+	return ms.initialLength
+}
+
+// GetTimeLimit is how long a round lasts at most. Zero means the round goes
+// on while there are snakes alive.
+func (ms *matchState) GetTimeLimit() time.Duration {
+	ms.stateSync.RLock()
+	defer ms.stateSync.RUnlock()
+
+	return ms.timeLimit
+}
+
+// GetCountdown is how long the countdown between everyone getting ready and
+// the round starting lasts. Zero means the round starts right away.
+func (ms *matchState) GetCountdown() time.Duration {
+	ms.stateSync.RLock()
+	defer ms.stateSync.RUnlock()
+
+	return ms.countdown
+}
+
+// GetSuddenDeath is how long the round goes on after the time limit, ending
+// as soon as at most one snake is left alive. Zero means the snakes alive
+// when the time is up die of timeout.
+func (ms *matchState) GetSuddenDeath() time.Duration {
+	ms.stateSync.RLock()
+	defer ms.stateSync.RUnlock()
+
+	return ms.suddenDeath
+}
+
+// GetResultsDuration is how long the results of a round are shown before the
+// match returns to the lobby.
+func (ms *matchState) GetResultsDuration() time.Duration {
+	ms.stateSync.RLock()
+	defer ms.stateSync.RUnlock()
+
+	return ms.resultsDuration
+}
+
+// GetSeriesLength is how many rounds the series of the match are the best
+// of, 1 unless set. A change takes effect on the next series.
+func (ms *matchState) GetSeriesLength() int {
+	ms.stateSync.RLock()
+	defer ms.stateSync.RUnlock()
+
+	if ms.seriesLength <= 0 {
+		return 1
+	}
+
+	return ms.seriesLength
+}
+
+// GetTeams is how many teams the players are split in, zero when they play
+// each for themselves.
+func (ms *matchState) GetTeams() int {
+	ms.stateSync.RLock()
+	defer ms.stateSync.RUnlock()
+
+	if ms.teams < 2 {
+		return 0
+	}
+
+	return min(ms.teams, MaxTeams)
+}
+
+// GetShrinkRules is how the arena shrinks during a round, never unless set.
+func (ms *matchState) GetShrinkRules() ShrinkRules {
+	ms.stateSync.RLock()
+	defer ms.stateSync.RUnlock()
+
+	return ms.shrink
+}
+
+// GetItemRules is how items spawn during a round, never unless set.
+func (ms *matchState) GetItemRules() ItemRules {
+	ms.stateSync.RLock()
+	defer ms.stateSync.RUnlock()
+
+	return ms.items
+}
+
+func (ms *matchState) GetCollisionRules() CollisionRules {
+	ms.stateSync.RLock()
+	defer ms.stateSync.RUnlock()
+
+	return ms.collisions
+}
+
 func (ms *matchState) GetStatus() matchStatus {
-	status := ms.status
-	return status
+	ms.stateSync.RLock()
+	defer ms.stateSync.RUnlock()
+
+	return ms.status
+}
+
+// GetReconnectGrace is how long a disconnected player keeps its place in the
+// match before being removed.
+func (ms *matchState) GetReconnectGrace() time.Duration {
+	ms.stateSync.RLock()
+	defer ms.stateSync.RUnlock()
+
+	return ms.reconnectGrace
+}
+
+// GetReconnectMode is who steers the snake of a disconnected player while
+// the match is running: ControlFrozen or ControlAutopilot.
+func (ms *matchState) GetReconnectMode() control {
+	ms.stateSync.RLock()
+	defer ms.stateSync.RUnlock()
+
+	return ms.reconnectMode
+}
+
+// GetMode names the way the match is played, as set by whoever created it.
+func (ms *matchState) GetMode() string {
+	ms.stateSync.RLock()
+	defer ms.stateSync.RUnlock()
+
+	return ms.mode
+}
+
+// IsPublic tells whether the match is listed for anyone to join.
+func (ms *matchState) IsPublic() bool {
+	ms.stateSync.RLock()
+	defer ms.stateSync.RUnlock()
+
+	return ms.public
+}
+
+// Admits tells whether the player can take a place in the match: anyone
+// can, unless the match is reserved to a roster of players.
+func (ms *matchState) Admits(playerID string) bool {
+	ms.stateSync.RLock()
+	defer ms.stateSync.RUnlock()
+
+	return len(ms.roster) == 0 || slices.Contains(ms.roster, playerID)
 }
@@ -0,0 +1,44 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/Maycon-Santos/go-snake-backend/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchState_UpdateState(t *testing.T) {
+	t.Run("should start in the lobby", func(t *testing.T) {
+		assert.Equal(t, StatusLobby, NewMatchState().GetStatus())
+	})
+
+	t.Run("should go through the legal transitions", func(t *testing.T) {
+		state := NewMatchState()
+
+		for _, status := range []matchStatus{StatusCountdown, StatusRunning, StatusSuddenDeath, StatusResults, StatusLobby, StatusClosed} {
+			assert.NoError(t, state.UpdateState(MatchStateInput{Status: utils.Ptr(status)}))
+			assert.Equal(t, status, state.GetStatus())
+		}
+	})
+
+	t.Run("should apply nothing on an illegal transition", func(t *testing.T) {
+		state := NewMatchState()
+
+		err := state.UpdateState(MatchStateInput{
+			Status:     utils.Ptr(StatusRunning),
+			FoodsLimit: utils.Ptr(3),
+		})
+
+		assert.ErrorIs(t, err, ErrIllegalTransition)
+		assert.EqualError(t, err, "match: illegal status transition: from LOBBY to RUNNING")
+		assert.Equal(t, StatusLobby, state.GetStatus())
+		assert.Zero(t, state.GetFoodsLimit())
+	})
+
+	t.Run("should not leave a closed match", func(t *testing.T) {
+		state := NewMatchState()
+
+		assert.NoError(t, state.UpdateState(MatchStateInput{Status: utils.Ptr(StatusClosed)}))
+		assert.ErrorIs(t, state.UpdateState(MatchStateInput{Status: utils.Ptr(StatusLobby)}), ErrIllegalTransition)
+	})
+}
@@ -0,0 +1,339 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Maycon-Santos/go-snake-backend/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch_Watch(t *testing.T) {
+	match := NewMatch("1", 2, 1)
+	defer match.Close()
+
+	t.Run("should not count spectators as players", func(t *testing.T) {
+		assert.NoError(t, match.Watch(NewSpectator("3", "spectator")))
+		assert.NoError(t, match.Enter(NewPlayer("1", "owner")))
+		assert.NoError(t, match.Enter(NewPlayer("2", "player")))
+
+		assert.Len(t, match.GetPlayers(), 2)
+		assert.Len(t, match.GetSpectators(), 1)
+		assert.Nil(t, match.GetPlayerByID("3"))
+	})
+
+	t.Run("should return an error when the spectators limit is reached", func(t *testing.T) {
+		assert.EqualError(
+			t,
+			match.Watch(NewSpectator("4", "spectator")),
+			"The match already has the maximum number of spectators (1)",
+		)
+	})
+
+	t.Run("should remove a spectator without touching the players", func(t *testing.T) {
+		match.RemovePlayer(*match.GetSpectatorByID("3"))
+
+		assert.Len(t, match.GetPlayers(), 2)
+		assert.Empty(t, match.GetSpectators())
+	})
+}
+
+func TestMatch_Disconnect(t *testing.T) {
+	match := NewMatch("1", 2, 0)
+	defer match.Close()
+
+	match.UpdateState(MatchStateInput{
+		ReconnectGrace: utils.Ptr(50 * time.Millisecond),
+	})
+
+	left := make(chan Player, 1)
+	match.OnLeave(func(player Player) {
+		left <- player
+	})
+
+	owner := NewPlayer("1", "owner")
+	assert.NoError(t, match.Enter(owner))
+
+	t.Run("should keep the player while it resumes within the grace", func(t *testing.T) {
+		match.Disconnect(owner)
+		assert.Eventually(t, func() bool { return !owner.IsConnected() }, time.Second, time.Millisecond)
+
+		assert.NoError(t, match.Resume(owner))
+		assert.True(t, owner.IsConnected())
+
+		select {
+		case <-left:
+			t.Fatal("the player should not have been removed")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		assert.Len(t, match.GetPlayers(), 1)
+	})
+
+	t.Run("should remove the player when the grace expires", func(t *testing.T) {
+		match.Disconnect(owner)
+
+		select {
+		case player := <-left:
+			assert.Equal(t, owner, player)
+		case <-time.After(time.Second):
+			t.Fatal("the player should have been removed")
+		}
+
+		assert.Empty(t, match.GetPlayers())
+		assert.Error(t, match.Resume(owner))
+	})
+}
+
+func TestMatch_Errors(t *testing.T) {
+	t.Run("should close the match and report a panic in a handler", func(t *testing.T) {
+		match := NewMatch("1", 2, 0)
+		defer match.Close()
+
+		match.OnLeave(func(player Player) {
+			panic("handler failed")
+		})
+
+		owner := NewPlayer("1", "owner")
+		assert.NoError(t, match.Enter(owner))
+
+		match.RemovePlayer(owner)
+
+		select {
+		case err := <-match.Errors():
+			var matchErr *Error
+			assert.ErrorAs(t, err, &matchErr)
+			assert.Equal(t, "1", matchErr.MatchID)
+			assert.EqualError(t, err, "recovered from panic: handler failed")
+		case <-time.After(time.Second):
+			t.Fatal("the panic should have been reported")
+		}
+
+		select {
+		case <-match.Done():
+		case <-time.After(time.Second):
+			t.Fatal("the match should have been closed")
+		}
+	})
+}
+
+func TestMatch_Lifecycle(t *testing.T) {
+	newLifecycleMatch := func(input MatchStateInput) (Match, Player, Player, chan matchStatus) {
+		match := NewMatch("1", 2, 0)
+
+		input.Map = &MapInput{Tiles: &Tiles{Horizontal: 20, Vertical: 20}}
+		match.UpdateState(input)
+
+		statuses := make(chan matchStatus, 16)
+		match.OnUpdateState(func() {
+			statuses <- match.GetStatus()
+		})
+
+		owner := NewPlayer("1", "owner")
+		player := NewPlayer("2", "player")
+		assert.NoError(t, match.Enter(owner))
+		assert.NoError(t, match.Enter(player))
+
+		return match, owner, player, statuses
+	}
+
+	awaitStatus := func(t *testing.T, statuses chan matchStatus, want matchStatus) {
+		for {
+			select {
+			case status := <-statuses:
+				if status == want {
+					return
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("the match should have reached %s", want)
+			}
+		}
+	}
+
+	t.Run("should count down and go back to the lobby when someone gets unready", func(t *testing.T) {
+		match, owner, player, statuses := newLifecycleMatch(MatchStateInput{
+			Countdown: utils.Ptr(10 * time.Second),
+		})
+		defer match.Close()
+
+		countdown := make(chan int, 1)
+		match.OnCountdown(func(remaining int) {
+			countdown <- remaining
+		})
+
+		match.Ready(owner)
+		match.Ready(player)
+
+		awaitStatus(t, statuses, StatusCountdown)
+		assert.Equal(t, 10, <-countdown)
+
+		match.Unready(player)
+
+		awaitStatus(t, statuses, StatusLobby)
+		assert.False(t, player.IsReady())
+	})
+
+	t.Run("should play the round, show the results and go back to the lobby", func(t *testing.T) {
+		match, owner, player, statuses := newLifecycleMatch(MatchStateInput{
+			TimeLimit:       utils.Ptr(100 * time.Millisecond),
+			SuddenDeath:     utils.Ptr(100 * time.Millisecond),
+			ResultsDuration: utils.Ptr(50 * time.Millisecond),
+		})
+		defer match.Close()
+
+		match.Ready(owner)
+		match.Ready(player)
+
+		for _, status := range []matchStatus{StatusCountdown, StatusRunning, StatusSuddenDeath, StatusResults, StatusLobby} {
+			awaitStatus(t, statuses, status)
+		}
+	})
+
+	t.Run("should be closed once closed", func(t *testing.T) {
+		match, _, _, _ := newLifecycleMatch(MatchStateInput{})
+		match.Close()
+
+		assert.Equal(t, StatusClosed, match.GetStatus())
+	})
+}
+
+func TestMatch_Loop(t *testing.T) {
+	// newRunningMatch has a round being played by two players, with the
+	// snake of the owner heading right. Its loop is not started, so the
+	// inputs are received and the ticks run by the test.
+	newRunningMatch := func() (*match, Player, Player) {
+		m := newMatch("1", 2, 0)
+		assert.True(t, m.setStatus(StatusCountdown))
+		assert.True(t, m.setStatus(StatusRunning))
+
+		owner := NewPlayer("1", "owner")
+		player := NewPlayer("2", "player")
+		assert.NoError(t, m.join(owner))
+		assert.NoError(t, m.join(player))
+
+		m.world = World{
+			Map: Map{Tiles: Tiles{Horizontal: 20, Vertical: 20}},
+			Snakes: []Snake{
+				{PlayerID: "1", Body: []BodyFragment{{X: 5, Y: 5}, {X: 4, Y: 5}}, Moving: MoveRight, Alive: true},
+				{PlayerID: "2", Body: []BodyFragment{{X: 5, Y: 15}, {X: 4, Y: 15}}, Moving: MoveRight, Alive: true},
+			},
+		}
+		m.recorder = newRecorder(1, Rules{}, 1, 0, 0, m.GetPlayers())
+
+		return m, owner, player
+	}
+
+	t.Run("should hold the inputs of a round until the next tick and apply them in order", func(t *testing.T) {
+		m, owner, player := newRunningMatch()
+
+		m.receive(input{kind: inputMove, player: owner, move: MoveUp})
+		m.receive(input{kind: inputMove, player: owner, move: MoveLeft})
+		m.receive(input{kind: inputLeave, player: player})
+
+		assert.Len(t, m.pending, 3)
+		assert.Len(t, m.GetPlayers(), 2)
+
+		m.tick()
+
+		assert.Empty(t, m.pending)
+		assert.Len(t, m.GetPlayers(), 1)
+		assert.Equal(t, BodyFragment{X: 5, Y: 4}, m.world.Snakes[0].Body[0])
+
+		m.tick()
+
+		assert.Equal(t, BodyFragment{X: 4, Y: 4}, m.world.Snakes[0].Body[0])
+	})
+
+	t.Run("should drop a move that arrives outside a round", func(t *testing.T) {
+		m, owner, _ := newRunningMatch()
+		assert.True(t, m.setStatus(StatusResults))
+
+		m.receive(input{kind: inputMove, player: owner, move: MoveUp})
+
+		assert.Empty(t, m.pending)
+		assert.Empty(t, m.world.Snakes[0].Movements)
+	})
+
+	t.Run("should not report the status changes of a match closed meanwhile", func(t *testing.T) {
+		m, _, _ := newRunningMatch()
+		m.Close()
+
+		assert.False(t, m.setStatus(StatusResults))
+		assert.Equal(t, StatusClosed, m.GetStatus())
+
+		select {
+		case err := <-m.Errors():
+			t.Fatalf("no error should have been reported, got %v", err)
+		default:
+		}
+	})
+}
+
+func TestMatch_Teams(t *testing.T) {
+	match := NewMatch("1", 4, 0)
+	defer match.Close()
+
+	match.UpdateState(MatchStateInput{Teams: utils.Ptr(2)})
+
+	players := []Player{NewPlayer("1", "owner"), NewPlayer("2", "b"), NewPlayer("3", "c"), NewPlayer("4", "d")}
+	for _, player := range players {
+		assert.NoError(t, match.Enter(player))
+	}
+
+	teams := func() []string {
+		teams := make([]string, 0, len(players))
+		for _, player := range players {
+			teams = append(teams, player.GetTeam())
+		}
+
+		return teams
+	}
+
+	t.Run("should put the players that join on the smallest team", func(t *testing.T) {
+		assert.Equal(t, []string{"red", "blue", "red", "blue"}, teams())
+	})
+
+	t.Run("should let only the owner assign a team", func(t *testing.T) {
+		match.AssignTeam(players[1], "3", "blue")
+		match.AssignTeam(players[0], "2", "red")
+
+		assert.Eventually(t, func() bool { return players[1].GetTeam() == "red" }, time.Second, time.Millisecond)
+		assert.Equal(t, "red", players[2].GetTeam())
+	})
+
+	t.Run("should balance the teams", func(t *testing.T) {
+		match.BalanceTeams(players[0])
+
+		assert.Eventually(t, func() bool {
+			red := 0
+			for _, team := range teams() {
+				if team == "red" {
+					red++
+				}
+			}
+
+			return red == 2
+		}, time.Second, time.Millisecond)
+	})
+}
+
+func TestMatch_Roster(t *testing.T) {
+	match := NewMatch("1", 3, 1)
+	defer match.Close()
+
+	match.UpdateState(MatchStateInput{Roster: []string{"1", "2"}})
+
+	t.Run("should let in the players of the roster", func(t *testing.T) {
+		assert.NoError(t, match.Enter(NewPlayer("1", "owner")))
+		assert.NoError(t, match.Enter(NewPlayer("2", "player")))
+	})
+
+	t.Run("should keep out the players that are not on the roster", func(t *testing.T) {
+		assert.ErrorIs(t, match.Enter(NewPlayer("3", "stranger")), errNotInRoster)
+		assert.Len(t, match.GetPlayers(), 2)
+	})
+
+	t.Run("should still let spectators watch", func(t *testing.T) {
+		assert.NoError(t, match.Watch(NewSpectator("4", "spectator")))
+	})
+}
@@ -2,22 +2,74 @@ package game
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"sync"
 
 	"github.com/Maycon-Santos/go-snake-backend/uuid"
 )
 
+type matchesEvent string
+
+const (
+	MatchAdded   = matchesEvent("ADDED")
+	MatchUpdated = matchesEvent("UPDATED")
+	MatchRemoved = matchesEvent("REMOVED")
+)
+
+// MatchesEvent tells that a match was added, removed, or that its state or
+// its players changed.
+type MatchesEvent struct {
+	Type  matchesEvent
+	Match Match
+}
+
+// MatchFilter selects the matches to list. Fields left empty select every
+// match.
+type MatchFilter struct {
+	Public *bool
+	Status *matchStatus
+	Mode   string
+	// Open selects the matches with room for another player.
+	Open bool
+}
+
+// Matches reports whether the match is selected by the filter.
+func (f MatchFilter) Matches(match Match) bool {
+	if f.Public != nil && match.IsPublic() != *f.Public {
+		return false
+	}
+
+	if f.Status != nil && match.GetStatus() != *f.Status {
+		return false
+	}
+
+	if f.Mode != "" && match.GetMode() != f.Mode {
+		return false
+	}
+
+	if f.Open && len(match.GetPlayers()) >= match.GetPlayersLimit() {
+		return false
+	}
+
+	return true
+}
+
 type Matches interface {
-	Add(playersLimit int) (Match, error)
+	Add(playersLimit, spectatorsLimit int) (Match, error)
 	GetMatchByID(id string) (Match, error)
 	GetMatchByOwnerID(ownerID string) (Match, error)
+	List(filter MatchFilter) []Match
 	DeleteByID(id string)
+	OnChange(fn func(event MatchesEvent))
 }
 
 type matches struct {
 	matches map[string]Match
 	sync    sync.Mutex
+
+	onChangeHandlers []func(event MatchesEvent)
+	onChangeSync     sync.Mutex
 }
 
 func NewMatches() Matches {
@@ -26,7 +78,7 @@ func NewMatches() Matches {
 	}
 }
 
-func (m *matches) Add(playersLimit int) (Match, error) {
+func (m *matches) Add(playersLimit, spectatorsLimit int) (Match, error) {
 	id, err := uuid.Generate()
 	if err != nil {
 		return nil, err
@@ -34,13 +86,32 @@ func (m *matches) Add(playersLimit int) (Match, error) {
 
 	idStr := strconv.FormatUint(*id, 10)
 
-	match := NewMatch(idStr, playersLimit)
-
-	m.matches[idStr] = match
+	match := NewMatch(idStr, playersLimit, spectatorsLimit)
+	m.put(match)
 
 	return match, nil
 }
 
+// put adds match and follows its changes.
+func (m *matches) put(match Match) {
+	m.sync.Lock()
+	m.matches[match.GetID()] = match
+	m.sync.Unlock()
+
+	// A closed match is about to be removed, which tells the change.
+	updated := func() {
+		if match.GetStatus() != StatusClosed {
+			m.dispatchChange(MatchesEvent{Type: MatchUpdated, Match: match})
+		}
+	}
+
+	match.OnUpdateState(updated)
+	match.OnJoin(func(Player) { updated() })
+	match.OnLeave(func(Player) { updated() })
+
+	m.dispatchChange(MatchesEvent{Type: MatchAdded, Match: match})
+}
+
 func (m *matches) GetMatchByID(id string) (Match, error) {
 	m.sync.Lock()
 	defer m.sync.Unlock()
@@ -71,9 +142,61 @@ func (m *matches) GetMatchByOwnerID(ownerID string) (Match, error) {
 	return nil, fmt.Errorf("matches: There is no match with id %s owner", ownerID)
 }
 
+// List returns the matches selected by filter, sorted by ID.
+func (m *matches) List(filter MatchFilter) []Match {
+	m.sync.Lock()
+	all := make([]Match, 0, len(m.matches))
+	for _, match := range m.matches {
+		all = append(all, match)
+	}
+	m.sync.Unlock()
+
+	sort.Slice(all, func(a, b int) bool {
+		return all[a].GetID() < all[b].GetID()
+	})
+
+	listed := make([]Match, 0, len(all))
+
+	for _, match := range all {
+		if filter.Matches(match) {
+			listed = append(listed, match)
+		}
+	}
+
+	return listed
+}
+
 func (m *matches) DeleteByID(id string) {
 	m.sync.Lock()
-	defer m.sync.Unlock()
 
-	delete(m.matches, id)
+	match, ok := m.matches[id]
+	if ok {
+		delete(m.matches, id)
+	}
+
+	m.sync.Unlock()
+
+	if ok {
+		match.Close()
+		m.dispatchChange(MatchesEvent{Type: MatchRemoved, Match: match})
+	}
+}
+
+// OnChange registers fn to be called whenever a match is added, removed or
+// changes. It is called from the goroutine of the match that changed, so it
+// must not block.
+func (m *matches) OnChange(fn func(event MatchesEvent)) {
+	m.onChangeSync.Lock()
+	defer m.onChangeSync.Unlock()
+
+	m.onChangeHandlers = append(m.onChangeHandlers, fn)
+}
+
+func (m *matches) dispatchChange(event MatchesEvent) {
+	m.onChangeSync.Lock()
+	defer m.onChangeSync.Unlock()
+
+	for _, fn := range m.onChangeHandlers {
+		fn(event)
+	}
 }
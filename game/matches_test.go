@@ -0,0 +1,61 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/Maycon-Santos/go-snake-backend/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatches(t *testing.T) {
+	t.Run("should list the matches selected by the filter", func(t *testing.T) {
+		matches := NewMatches().(*matches)
+
+		public := NewMatch("1", 2, 0)
+		public.UpdateState(MatchStateInput{Public: utils.Ptr(true), Mode: utils.Ptr("custom")})
+		matches.put(public)
+
+		duel := NewMatch("2", 2, 0)
+		duel.UpdateState(MatchStateInput{Public: utils.Ptr(true), Mode: utils.Ptr("duel")})
+		matches.put(duel)
+
+		private := NewMatch("3", 2, 0)
+		matches.put(private)
+
+		defer matches.DeleteByID(public.GetID())
+		defer matches.DeleteByID(duel.GetID())
+		defer matches.DeleteByID(private.GetID())
+
+		assert.Len(t, matches.List(MatchFilter{}), 3)
+
+		listed := matches.List(MatchFilter{Public: utils.Ptr(true)})
+		assert.Len(t, listed, 2)
+
+		listed = matches.List(MatchFilter{Public: utils.Ptr(true), Mode: "duel"})
+		assert.Equal(t, []Match{duel}, listed)
+
+		listed = matches.List(MatchFilter{Status: utils.Ptr(StatusRunning)})
+		assert.Empty(t, listed)
+	})
+
+	t.Run("should tell when a match is added, changes and is removed", func(t *testing.T) {
+		matches := NewMatches().(*matches)
+		events := make(chan MatchesEvent, 8)
+
+		matches.OnChange(func(event MatchesEvent) {
+			events <- event
+		})
+
+		match := NewMatch("1", 2, 0)
+		matches.put(match)
+		assert.Equal(t, MatchAdded, (<-events).Type)
+
+		match.UpdateState(MatchStateInput{Public: utils.Ptr(true)})
+		assert.Equal(t, MatchUpdated, (<-events).Type)
+
+		matches.DeleteByID(match.GetID())
+		event := <-events
+		assert.Equal(t, MatchRemoved, event.Type)
+		assert.Equal(t, match, event.Match)
+	})
+}
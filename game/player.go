@@ -1,60 +1,68 @@
 package game
 
 import (
-	"encoding/json"
-	"math"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"sync"
+	"time"
 
-	"github.com/Maycon-Santos/go-snake-backend/utils"
 	"github.com/gorilla/websocket"
-	"golang.org/x/exp/slices"
 )
 
 type Player interface {
 	Reset()
-	AddMovement(mv movement)
-	SendMessage(message []byte) error
+	SendMessage(message interface{}) error
+	SendEncoded(codec Codec, data []byte) error
+	SendSnapshot(codec Codec, data []byte, keyframe bool) error
+	GetCodec() Codec
 	SetMatch(room Match)
-	SetSocket(socket *websocket.Conn)
-	ToIncrease(toIncrease uint)
-	Increase()
-	DieOnPlayerCollision()
+	SetSocket(socket *websocket.Conn, codec Codec)
 	GetID() string
 	GetName() string
-	GenerateInitialBody(n int)
-	Move()
-	TeleportCornerScreen()
-	OnDie(fn func())
+	GetResumeToken() string
+	IsSpectator() bool
+	ErrorSink
 	PlayerState
 }
 
 type messageListener = func(message WrittenMessage)
 
 type player struct {
-	id     string
-	name   string
-	socket *websocket.Conn
+	id          string
+	name        string
+	resumeToken string
+	spectator   bool
+	conn        *connection
 
 	match Match
 
+	*errorSink
+
 	messageListeners []messageListener
 	sendMessageSync  sync.Mutex
 
-	onDieHandlers []func()
-
-	toIncrease uint
+	PlayerState
+}
 
-	movementSync sync.Mutex
-	movements    []movement
-	moving       movement
-	lastTail     BodyFragment
+var (
+	errSocketNotSet     = errors.New("player: the socket has not been set")
+	errSendQueueFull    = errors.New("player: the send queue is full")
+	errConnectionClosed = errors.New("player: the connection is closed")
+	expectedCloseCodes  = []int{websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseNoStatusReceived}
+)
 
-	PlayerState
+// TeamAssignment is the owner of a match moving a player to a team.
+type TeamAssignment struct {
+	PlayerID string `json:"playerId" bin:"1"`
+	Team     string `json:"team" bin:"2"`
 }
 
 type WrittenMessage struct {
-	MoveTo string `json:"moveTo,omitempty"`
-	Ready  *bool  `json:"ready,omitempty"`
+	MoveTo       string          `json:"moveTo,omitempty" bin:"1"`
+	Ready        *bool           `json:"ready,omitempty" bin:"2"`
+	Team         *TeamAssignment `json:"team,omitempty" bin:"3"`
+	BalanceTeams bool            `json:"balanceTeams,omitempty" bin:"4"`
 }
 
 type movement int
@@ -75,43 +83,85 @@ func NewPlayer(id, name string) Player {
 	return &player{
 		id:          id,
 		name:        name,
-		moving:      MoveRight,
+		resumeToken: newResumeToken(),
+		errorSink:   newErrorSink(),
+		PlayerState: newPlayerState(),
+	}
+}
+
+// NewSpectator creates a player that only watches the match: it never gets
+// a snake and its moves and ready changes are ignored.
+func NewSpectator(id, name string) Player {
+	return &player{
+		id:          id,
+		name:        name,
+		spectator:   true,
+		errorSink:   newErrorSink(),
 		PlayerState: newPlayerState(),
 	}
 }
 
-func (p *player) SetSocket(socket *websocket.Conn) {
+// newResumeToken draws the secret a player must present to take its place in
+// the match back after losing the connection.
+func newResumeToken() string {
+	token := make([]byte, 16)
+
+	if _, err := rand.Read(token); err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(token)
+}
+
+// SetSocket makes socket the connection of the player, closing the one it
+// replaces.
+func (p *player) SetSocket(socket *websocket.Conn, codec Codec) {
 	p.sendMessageSync.Lock()
 	defer p.sendMessageSync.Unlock()
 
-	p.socket = socket
-	p.startListening()
+	if p.conn != nil {
+		p.conn.close()
+	}
+
+	p.conn = newConnection(socket, codec, p.reportError)
+	p.startListening(p.conn)
 }
 
 func (p *player) Reset() {
-	p.moving = MoveRight
-	p.movements = make([]movement, 0)
 	p.UpdateState(PlayerStateInput{
-		Body: nil,
+		Body:    []BodyFragment{},
+		Effects: []Effect{},
 	})
 }
 
-func (p *player) startListening() {
+func (p *player) startListening(conn *connection) {
+	socket := conn.socket
+
+	socket.SetReadDeadline(time.Now().Add(pongWait))
+	socket.SetPongHandler(func(string) error {
+		return socket.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
 	go (func() {
 		for {
-			messageType, reader, err := p.socket.NextReader()
+			messageType, data, err := socket.ReadMessage()
 			if err != nil {
-				// Enviar erros para um chan
+				if websocket.IsUnexpectedCloseError(err, expectedCloseCodes...) {
+					p.reportError(err)
+				}
+
+				conn.close()
+				p.disconnect(conn, err)
 				return
 			}
 
-			if messageType == websocket.TextMessage {
+			if messageType == conn.codec.MessageType() {
 				message := WrittenMessage{}
 
-				err = json.NewDecoder(reader).Decode(&message)
+				err = conn.codec.Decode(data, &message)
 				if err != nil {
-					// Enviar erros para um chan
-					return
+					p.reportError(err)
+					continue
 				}
 
 				p.readMessages(message)
@@ -121,231 +171,156 @@ func (p *player) startListening() {
 }
 
 func (p *player) readMessages(message WrittenMessage) {
+	match := p.getMatch()
+	if p.spectator || match == nil {
+		return
+	}
+
 	switch message.MoveTo {
 	case "right":
-		p.AddMovement(MoveRight)
+		match.Move(p, MoveRight)
 	case "left":
-		p.AddMovement(MoveLeft)
+		match.Move(p, MoveLeft)
 	case "up":
-		p.AddMovement(MoveUp)
+		match.Move(p, MoveUp)
 	case "down":
-		p.AddMovement(MoveDown)
+		match.Move(p, MoveDown)
 	}
 
-	if p.match.GetStatus() == StatusOnHold {
-		if message.Ready != nil && *message.Ready {
-			p.UpdateState(PlayerStateInput{
-				IsReady: message.Ready,
-			})
-
-			if *message.Ready {
-				p.match.Ready()
-			} else {
-				p.match.Unready()
-			}
+	if message.Ready != nil {
+		if *message.Ready {
+			match.Ready(p)
+		} else {
+			match.Unready(p)
 		}
 	}
-}
-
-// Enviar erros para um chan
-
-func (p *player) SendMessage(message []byte) error {
-	p.sendMessageSync.Lock()
-	defer p.sendMessageSync.Unlock()
 
-	writer, err := p.socket.NextWriter(websocket.TextMessage)
-	if err != nil {
-		return err
+	if message.Team != nil {
+		match.AssignTeam(p, message.Team.PlayerID, message.Team.Team)
 	}
 
-	defer writer.Close()
-
-	_, err = writer.Write(message)
-	if err != nil {
-		return err
+	if message.BalanceTeams {
+		match.BalanceTeams(p)
 	}
-
-	return nil
 }
 
-func (p *player) SetMatch(match Match) {
-	p.match = match
-}
-
-func (p *player) GetID() string {
-	return p.id
-}
-
-func (p *player) GetName() string {
-	return p.name
-}
-
-func (p *player) AddMovement(mv movement) {
-	p.movementSync.Lock()
-	defer p.movementSync.Unlock()
+// disconnect tells the match that the player lost conn. Closing the socket
+// with a normal closure while the match is on hold means the player left on
+// purpose; any other way out gives the player the reconnect grace.
+// Connections that were already replaced are ignored.
+func (p *player) disconnect(conn *connection, err error) {
+	p.sendMessageSync.Lock()
+	current := p.conn == conn
+	match := p.match
+	p.sendMessageSync.Unlock()
 
-	nextMovement := p.moving
-	if len(p.movements) > 0 {
-		nextMovement = p.movements[0]
+	if !current || match == nil {
+		return
 	}
 
-	if slices.Contains(horizontalMovements, nextMovement) && slices.Contains(horizontalMovements, mv) {
+	if p.spectator {
+		match.RemovePlayer(p)
 		return
 	}
 
-	if slices.Contains(VerticalMovements, nextMovement) && slices.Contains(VerticalMovements, mv) {
+	if websocket.IsCloseError(err, websocket.CloseNormalClosure) && !match.GetStatus().IsPlaying() {
+		match.RemovePlayer(p)
 		return
 	}
 
-	p.movements = append(p.movements, mv)
-}
-
-func (p *player) ToIncrease(toIncrease uint) {
-	p.toIncrease += toIncrease
+	match.Disconnect(p)
 }
 
-func (p *player) Move() {
-	if !p.IsAlive() {
-		return
+// reportError delivers err to the error sink of the player, tagged with the
+// player and its match.
+func (p *player) reportError(err error) {
+	e := &Error{
+		PlayerID: p.id,
+		Err:      err,
 	}
 
-	p.movementSync.Lock()
-	if len(p.movements) > 0 {
-		p.moving, p.movements = p.movements[0], p.movements[1:]
+	if match := p.getMatch(); match != nil {
+		e.MatchID = match.GetID()
 	}
-	p.movementSync.Unlock()
 
-	body := p.GetBody()
+	p.report(e)
+}
 
-	var newBodyFragment BodyFragment
+func (p *player) SendMessage(message interface{}) error {
+	codec := p.GetCodec()
 
-	switch p.moving {
-	case MoveRight:
-		newBodyFragment = BodyFragment{
-			body[0].X + 1,
-			body[0].Y,
-		}
-	case MoveLeft:
-		newBodyFragment = BodyFragment{
-			body[0].X - 1,
-			body[0].Y,
-		}
-	case MoveUp:
-		newBodyFragment = BodyFragment{
-			body[0].X,
-			body[0].Y - 1,
-		}
-	case MoveDown:
-		newBodyFragment = BodyFragment{
-			body[0].X,
-			body[0].Y + 1,
-		}
+	data, err := codec.Encode(message)
+	if err != nil {
+		return err
 	}
 
-	p.lastTail = body[len(p.GetBody())-1]
-
-	p.UpdateState(PlayerStateInput{
-		Body: append([]BodyFragment{newBodyFragment}, body[:len(p.GetBody())-1]...),
-	})
+	return p.SendEncoded(codec, data)
 }
 
-func (p *player) TeleportCornerScreen() {
-	if !p.IsAlive() {
-		return
-	}
-
-	body := p.GetBody()
-	head := body[0]
-	tiles := p.match.GetMap().Tiles
+// SendEncoded queues a message already encoded with codec, which lets a
+// match encode a message once for all the players that share a codec.
+func (p *player) SendEncoded(codec Codec, data []byte) error {
+	return p.send(outgoing{codec: codec, data: data})
+}
 
-	if head.X >= tiles.Horizontal {
-		p.UpdateState(PlayerStateInput{
-			Body: append([]BodyFragment{{X: 0, Y: head.Y}}, body[1:]...),
-		})
-	}
+// SendSnapshot queues an encoded snapshot. Unlike other messages, snapshots
+// may be dropped when the player cannot keep up with the match.
+func (p *player) SendSnapshot(codec Codec, data []byte, keyframe bool) error {
+	return p.send(outgoing{codec: codec, data: data, snapshot: true, keyframe: keyframe})
+}
 
-	if head.X < 0 {
-		p.UpdateState(PlayerStateInput{
-			Body: append([]BodyFragment{{X: tiles.Horizontal - 1, Y: head.Y}}, body[1:]...),
-		})
-	}
+func (p *player) send(msg outgoing) error {
+	p.sendMessageSync.Lock()
+	conn := p.conn
+	p.sendMessageSync.Unlock()
 
-	if head.Y >= tiles.Vertical {
-		p.UpdateState(PlayerStateInput{
-			Body: append([]BodyFragment{{X: head.X, Y: 0}}, body[1:]...),
-		})
+	if conn == nil {
+		return errSocketNotSet
 	}
 
-	if head.Y < 0 {
-		p.UpdateState(PlayerStateInput{
-			Body: append([]BodyFragment{{X: head.X, Y: tiles.Vertical - 1}}, body[1:]...),
-		})
-	}
+	return conn.send(msg)
 }
 
-func (p *player) Increase() {
-	if !p.IsAlive() {
-		return
-	}
-
-	if p.toIncrease > 0 {
-		p.toIncrease -= 1
+func (p *player) GetCodec() Codec {
+	p.sendMessageSync.Lock()
+	defer p.sendMessageSync.Unlock()
 
-		p.UpdateState(PlayerStateInput{
-			Body: append(p.GetBody(), p.lastTail),
-		})
+	if p.conn == nil {
+		return JSONCodec
 	}
-}
 
-func (p *player) OnDie(fn func()) {
-	p.onDieHandlers = append(p.onDieHandlers, fn)
+	return p.conn.codec
 }
 
-func (p *player) DieOnPlayerCollision() {
-	if !p.IsAlive() {
-		return
-	}
-
-	head := p.GetBody()[0]
-
-	for _, player := range p.match.GetPlayers() {
-		if !player.IsAlive() {
-			continue
-		}
+// SetMatch makes match the one the player takes part in. The reader of the
+// connection may be running already, so the match is kept under the same
+// lock as the connection.
+func (p *player) SetMatch(match Match) {
+	p.sendMessageSync.Lock()
+	defer p.sendMessageSync.Unlock()
 
-		for j, bodyFragment := range player.GetBody() {
-			if j == 0 && player.GetID() == p.id {
-				continue
-			}
+	p.match = match
+}
 
-			collided := bodyFragment.X == head.X && bodyFragment.Y == head.Y
+func (p *player) getMatch() Match {
+	p.sendMessageSync.Lock()
+	defer p.sendMessageSync.Unlock()
 
-			if collided {
-				p.movements = make([]movement, 0)
-				p.UpdateState(PlayerStateInput{
-					IsAlive: utils.Ptr(false),
-				})
+	return p.match
+}
 
-				for _, fn := range p.onDieHandlers {
-					fn()
-				}
-			}
-		}
-	}
+func (p *player) GetID() string {
+	return p.id
 }
 
-func (p *player) GenerateInitialBody(n int) {
-	xMultiplier := (n % 3) + 1
-	yMultiplier := math.Ceil(float64(n+1) / 3)
+func (p *player) GetName() string {
+	return p.name
+}
 
-	xBodyStart := int(16 * xMultiplier)
-	yBodyStart := int(9 * yMultiplier)
+func (p *player) GetResumeToken() string {
+	return p.resumeToken
+}
 
-	p.UpdateState(PlayerStateInput{
-		Body: []BodyFragment{
-			{X: xBodyStart, Y: yBodyStart},
-			{X: xBodyStart - 1, Y: yBodyStart},
-			{X: xBodyStart - 2, Y: yBodyStart},
-		},
-	})
+func (p *player) IsSpectator() bool {
+	return p.spectator
 }
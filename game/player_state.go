@@ -1,19 +1,18 @@
 package game
 
-import (
-	"log"
-	"sync"
-)
+import "sync"
 
 type PlayerState interface {
 	UpdateState(input PlayerStateInput)
 	OnUpdateState(fn func())
 	IsReady() bool
 	IsAlive() bool
+	IsConnected() bool
 	GetBody() []BodyFragment
+	GetTeam() string
+	GetEffects() []Effect
 
-	OpenBatch()
-	CloseBatch()
+	setState(input PlayerStateInput)
 }
 
 type BodyFragment struct {
@@ -24,49 +23,42 @@ type BodyFragment struct {
 type playerState struct {
 	isAlive          bool
 	isReady          bool
+	isConnected      bool
+	team             string
 	body             []BodyFragment
+	effects          []Effect
 	onUpdateHandlers []func()
 
-	sync sync.Mutex
-
-	isBatching   bool
-	onCloseBatch func()
+	sync      sync.Mutex
+	stateSync sync.RWMutex
 }
 
 type PlayerStateInput struct {
-	IsAlive *bool
-	IsReady *bool
-	Body    []BodyFragment
+	IsAlive     *bool
+	IsReady     *bool
+	IsConnected *bool
+	Team        *string
+	Body        []BodyFragment
+	Effects     []Effect
 }
 
 func newPlayerState() PlayerState {
-	return &playerState{}
-}
-
-func (ps *playerState) OpenBatch() {
-	if ps.isBatching {
-		log.Fatal("There is already an open batch")
-		return
+	return &playerState{
+		isConnected: true,
 	}
-
-	ps.isBatching = true
 }
 
-func (ps *playerState) CloseBatch() {
-	if !ps.isBatching {
-		log.Fatal("The batch needs to be opened first")
-		return
-	}
-
-	ps.isBatching = false
-
-	if ps.onCloseBatch != nil {
-		ps.onCloseBatch()
-		ps.onCloseBatch = nil
-	}
+func (ps *playerState) UpdateState(input PlayerStateInput) {
+	ps.setState(input)
+	ps.dispatchUpdateEvent()
 }
 
-func (ps *playerState) UpdateState(input PlayerStateInput) {
+// setState updates the state without dispatching the update event. The match
+// uses it while running, when the changes are broadcast as snapshots.
+func (ps *playerState) setState(input PlayerStateInput) {
+	ps.stateSync.Lock()
+	defer ps.stateSync.Unlock()
+
 	if input.IsReady != nil {
 		ps.isReady = *input.IsReady
 	}
@@ -75,14 +67,20 @@ func (ps *playerState) UpdateState(input PlayerStateInput) {
 		ps.isAlive = *input.IsAlive
 	}
 
+	if input.IsConnected != nil {
+		ps.isConnected = *input.IsConnected
+	}
+
+	if input.Team != nil {
+		ps.team = *input.Team
+	}
+
 	if input.Body != nil {
 		ps.body = input.Body
 	}
 
-	if ps.isBatching {
-		ps.onCloseBatch = ps.dispatchUpdateEvent
-	} else {
-		ps.dispatchUpdateEvent()
+	if input.Effects != nil {
+		ps.effects = input.Effects
 	}
 }
 
@@ -105,13 +103,47 @@ func (ps *playerState) OnUpdateState(fn func()) {
 }
 
 func (ps *playerState) GetBody() []BodyFragment {
+	ps.stateSync.RLock()
+	defer ps.stateSync.RUnlock()
+
 	return ps.body
 }
 
 func (ps *playerState) IsReady() bool {
+	ps.stateSync.RLock()
+	defer ps.stateSync.RUnlock()
+
 	return ps.isReady
 }
 
 func (ps *playerState) IsAlive() bool {
+	ps.stateSync.RLock()
+	defer ps.stateSync.RUnlock()
+
 	return ps.isAlive
 }
+
+func (ps *playerState) IsConnected() bool {
+	ps.stateSync.RLock()
+	defer ps.stateSync.RUnlock()
+
+	return ps.isConnected
+}
+
+// GetTeam is the team of the player in a match split in teams, empty
+// otherwise.
+func (ps *playerState) GetTeam() string {
+	ps.stateSync.RLock()
+	defer ps.stateSync.RUnlock()
+
+	return ps.team
+}
+
+// GetEffects are the effects of the items the snake of the player picked up
+// that are still acting on it.
+func (ps *playerState) GetEffects() []Effect {
+	ps.stateSync.RLock()
+	defer ps.stateSync.RUnlock()
+
+	return ps.effects
+}
@@ -0,0 +1,31 @@
+package game
+
+// Rand is a deterministic pseudo-random generator (SplitMix64). Its whole
+// state is a single value, so copying a World also copies the position of
+// its generator and a simulation can be replayed from the same seed.
+type Rand struct {
+	State uint64
+}
+
+func NewRand(seed int64) Rand {
+	return Rand{State: uint64(seed)}
+}
+
+func (r *Rand) Uint64() uint64 {
+	r.State += 0x9e3779b97f4a7c15
+
+	z := r.State
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+
+	return z ^ (z >> 31)
+}
+
+// Intn returns a number in [0, n). It panics if n <= 0.
+func (r *Rand) Intn(n int) int {
+	if n <= 0 {
+		panic("rand: invalid argument to Intn")
+	}
+
+	return int(r.Uint64() % uint64(n))
+}
@@ -0,0 +1,183 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Replay is everything needed to simulate a round again: the world is built
+// from the seed, map and players, and stepped with the recorded inputs.
+// TimeLimit and SuddenDeath are how many ticks the round and its sudden
+// death lasted at most, the round having no time limit when zero.
+type Replay struct {
+	Seed           int64  `json:"seed"`
+	TicksPerSecond int    `json:"ticks_per_second"`
+	TimeLimit      uint64 `json:"time_limit,omitempty"`
+	SuddenDeath    uint64 `json:"sudden_death,omitempty"`
+	Rules
+	Players []ReplayPlayer `json:"players"`
+	Ticks   uint64         `json:"ticks"`
+	Inputs  []ReplayInput  `json:"inputs"`
+}
+
+type ReplayPlayer struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ReplayInput is encoded as [tick, player index, move] to keep the log small,
+// or as [tick, player index, move, control] when it changes who is steering
+// the snake.
+type ReplayInput struct {
+	Tick    uint64
+	Player  int
+	Move    movement
+	Control *control
+}
+
+type recorder struct {
+	replay  Replay
+	players map[string]int
+}
+
+func newRecorder(seed int64, rules Rules, ticksPerSecond int, timeLimit, suddenDeath uint64, players []Player) *recorder {
+	r := &recorder{
+		replay: Replay{
+			Seed:           seed,
+			TicksPerSecond: ticksPerSecond,
+			TimeLimit:      timeLimit,
+			SuddenDeath:    suddenDeath,
+			Rules:          rules,
+			Players:        make([]ReplayPlayer, 0, len(players)),
+			Inputs:         make([]ReplayInput, 0),
+		},
+		players: make(map[string]int),
+	}
+
+	for i, player := range players {
+		r.players[player.GetID()] = i
+		r.replay.Players = append(r.replay.Players, ReplayPlayer{
+			ID:   player.GetID(),
+			Name: player.GetName(),
+		})
+	}
+
+	return r
+}
+
+func (r *recorder) record(tick uint64, inputs []Input) {
+	r.replay.Ticks = tick
+
+	for _, input := range inputs {
+		player, ok := r.players[input.PlayerID]
+		if !ok {
+			continue
+		}
+
+		r.replay.Inputs = append(r.replay.Inputs, ReplayInput{
+			Tick:    tick,
+			Player:  player,
+			Move:    input.Move,
+			Control: input.Control,
+		})
+	}
+}
+
+// TickRate is how many ticks a second the round was played at, the default
+// tick rate for a replay that does not tell.
+func (r Replay) TickRate() int {
+	if r.TicksPerSecond <= 0 {
+		return defaultTickRate
+	}
+
+	return r.TicksPerSecond
+}
+
+func (r Replay) World() World {
+	playerIDs := make([]string, 0, len(r.Players))
+
+	for _, player := range r.Players {
+		playerIDs = append(playerIDs, player.ID)
+	}
+
+	return NewWorld(r.Seed, r.Rules, playerIDs)
+}
+
+// Play simulates the replay again, calling fn with the initial world and
+// then with the world and events of every recorded tick. Like the match
+// loop, it kills the snakes still alive once the time limit and the sudden
+// death are over. It stops as soon as fn returns false.
+func (r Replay) Play(fn func(world World, events []Event) bool) {
+	world := r.World()
+
+	if !fn(world, nil) {
+		return
+	}
+
+	next := 0
+
+	for tick := uint64(1); tick <= r.Ticks; tick++ {
+		inputs := make([]Input, 0)
+
+		for ; next < len(r.Inputs) && r.Inputs[next].Tick == tick; next++ {
+			input := r.Inputs[next]
+
+			if input.Player < 0 || input.Player >= len(r.Players) {
+				continue
+			}
+
+			inputs = append(inputs, Input{
+				PlayerID: r.Players[input.Player].ID,
+				Move:     input.Move,
+				Control:  input.Control,
+			})
+		}
+
+		var events []Event
+		world, events = Step(world, inputs)
+
+		if r.TimeLimit > 0 && world.Tick == r.TimeLimit+r.SuddenDeath && world.HasAliveSnakes() {
+			var timeoutEvents []Event
+			world, timeoutEvents = TimeOut(world)
+			events = append(events, timeoutEvents...)
+		}
+
+		if !fn(world, events) {
+			return
+		}
+	}
+}
+
+func (ri ReplayInput) MarshalJSON() ([]byte, error) {
+	fields := []uint64{ri.Tick, uint64(ri.Player), uint64(ri.Move)}
+
+	if ri.Control != nil {
+		fields = append(fields, uint64(*ri.Control))
+	}
+
+	return json.Marshal(fields)
+}
+
+func (ri *ReplayInput) UnmarshalJSON(data []byte) error {
+	var fields []uint64
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	if len(fields) != 3 && len(fields) != 4 {
+		return fmt.Errorf("replay: an input must have 3 or 4 fields, got %d", len(fields))
+	}
+
+	ri.Tick = fields[0]
+	ri.Player = int(fields[1])
+	ri.Move = movement(fields[2])
+	ri.Control = nil
+
+	if len(fields) == 4 {
+		c := control(fields[3])
+		ri.Control = &c
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplay(t *testing.T) {
+	players := []Player{NewPlayer("1", "michael"), NewPlayer("2", "jordan")}
+	world := newTestWorld(7, "1", "2")
+	recorder := newRecorder(7, testRules, defaultTickRate, 0, 0, players)
+
+	inputs := [][]Input{
+		{{PlayerID: "1", Move: MoveUp}},
+		{{PlayerID: "2", Move: MoveDown}},
+		{},
+		{{PlayerID: "1", Move: MoveRight}, {PlayerID: "2", Move: MoveLeft}},
+	}
+
+	for i := 0; i < 100; i++ {
+		tickInputs := inputs[i%len(inputs)]
+		world, _ = Step(world, tickInputs)
+		recorder.record(world.Tick, tickInputs)
+	}
+
+	t.Run("should simulate the same world again", func(t *testing.T) {
+		var last World
+
+		recorder.replay.Play(func(world World, events []Event) bool {
+			last = world
+			return true
+		})
+
+		assert.Equal(t, world, last)
+	})
+
+	t.Run("should keep the same log after encoding it", func(t *testing.T) {
+		data, err := json.Marshal(recorder.replay)
+		assert.NoError(t, err)
+
+		var replay Replay
+		assert.NoError(t, json.Unmarshal(data, &replay))
+
+		assert.Equal(t, recorder.replay, replay)
+	})
+
+	t.Run("should stop when asked to", func(t *testing.T) {
+		calls := 0
+
+		recorder.replay.Play(func(world World, events []Event) bool {
+			calls++
+			return calls < 10
+		})
+
+		assert.Equal(t, 10, calls)
+	})
+
+	t.Run("should time out the snakes on the tick the match did", func(t *testing.T) {
+		for _, suddenDeath := range []uint64{0, 5} {
+			world := newTestWorld(7, "1", "2")
+			recorder := newRecorder(7, testRules, defaultTickRate, 10, suddenDeath, players)
+
+			for world.Tick < 10+suddenDeath {
+				world, _ = Step(world, nil)
+				recorder.record(world.Tick, nil)
+			}
+
+			world, _ = TimeOut(world)
+
+			var last World
+			var died []Event
+
+			recorder.replay.Play(func(world World, events []Event) bool {
+				last = world
+				died = append(died, eventsOf(events, EventDied)...)
+				return true
+			})
+
+			assert.Equal(t, world, last)
+			assert.Len(t, died, 2)
+			assert.Equal(t, CauseTimeout, died[0].Cause)
+		}
+	})
+
+	t.Run("should be played at the default tick rate when it does not tell", func(t *testing.T) {
+		assert.Equal(t, defaultTickRate, Replay{}.TickRate())
+		assert.Equal(t, defaultTickRate, Replay{TicksPerSecond: -3}.TickRate())
+		assert.Equal(t, 30, Replay{TicksPerSecond: 30}.TickRate())
+	})
+}
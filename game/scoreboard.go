@@ -0,0 +1,234 @@
+package game
+
+import (
+	"sort"
+	"time"
+)
+
+// Score is how a player is doing in a round. Placement is zero while the
+// snake is alive; the last snakes to die are placed first.
+type Score struct {
+	PlayerID      string
+	Length        int
+	FoodEaten     int
+	Bonus         int
+	Kills         int
+	SurvivalTicks uint64
+	Placement     int
+	Cause         deathCause
+	KillerID      string
+	Team          string
+}
+
+// Scoreboard holds the score of every player of a round, in the order of the
+// snakes. Like World, it is a plain value: Record returns a new one.
+type Scoreboard []Score
+
+// NewScoreboard starts the scores of a round. The snakes that had no room
+// to spawn are placed last right away.
+func NewScoreboard(world World) Scoreboard {
+	scoreboard := make(Scoreboard, 0, len(world.Snakes))
+	alive := 0
+
+	for _, snake := range world.Snakes {
+		if snake.Alive {
+			alive++
+		}
+	}
+
+	for _, snake := range world.Snakes {
+		score := Score{
+			PlayerID: snake.PlayerID,
+			Length:   len(snake.Body),
+			Team:     snake.Team,
+		}
+
+		if !snake.Alive {
+			score.Placement = alive + 1
+		}
+
+		scoreboard = append(scoreboard, score)
+	}
+
+	return scoreboard
+}
+
+// Result is how a round ended, along with the series it is part of.
+type Result struct {
+	Ticks      uint64
+	StartedAt  time.Time
+	EndedAt    time.Time
+	Scoreboard Scoreboard
+	Series     Series
+}
+
+// Record updates the scores with a tick that turned the world into world
+// and produced events.
+//
+// Snakes that died in the same tick are placed after the ones still alive,
+// the longer ones first. Snakes that died with the same length share their
+// placement.
+func (s Scoreboard) Record(world World, events []Event) Scoreboard {
+	scoreboard := make(Scoreboard, len(s))
+	copy(scoreboard, s)
+
+	index := make(map[string]int, len(scoreboard))
+	for i, score := range scoreboard {
+		index[score.PlayerID] = i
+	}
+
+	dead := make([]int, 0)
+
+	for _, event := range events {
+		i, ok := index[event.PlayerID]
+		if !ok {
+			continue
+		}
+
+		switch event.Type {
+		case EventAte:
+			scoreboard[i].FoodEaten++
+		case EventPicked:
+			if event.Item == ItemGolden {
+				scoreboard[i].Bonus += goldenBonus
+			}
+		case EventDied:
+			scoreboard[i].Cause = event.Cause
+			scoreboard[i].KillerID = event.KillerID
+			dead = append(dead, i)
+
+			if killer, ok := index[event.KillerID]; ok {
+				scoreboard[killer].Kills++
+			}
+		}
+	}
+
+	alive := 0
+
+	for _, snake := range world.Snakes {
+		i, ok := index[snake.PlayerID]
+		if !ok {
+			continue
+		}
+
+		if snake.Alive || scoreboard[i].Placement == 0 {
+			scoreboard[i].Length = len(snake.Body)
+			scoreboard[i].SurvivalTicks = world.Tick
+		}
+
+		if snake.Alive {
+			alive++
+		}
+	}
+
+	sort.SliceStable(dead, func(a, b int) bool {
+		return scoreboard[dead[a]].Length > scoreboard[dead[b]].Length
+	})
+
+	for n, i := range dead {
+		placement := alive + n + 1
+		if n > 0 && scoreboard[dead[n-1]].Length == scoreboard[i].Length {
+			placement = scoreboard[dead[n-1]].Placement
+		}
+
+		scoreboard[i].Placement = placement
+	}
+
+	return scoreboard
+}
+
+// Finish places first the snakes still alive when a round ends before they
+// all died, as it does when a single snake is left in sudden death.
+//
+// When the snakes play in teams, the whole winning team is placed first and
+// the other snakes after it. A team wins when it is the last one with snakes
+// alive or, when that does not tell, by the combined length of its snakes.
+func (s Scoreboard) Finish() Scoreboard {
+	scoreboard := make(Scoreboard, len(s))
+	copy(scoreboard, s)
+
+	if team, ok := scoreboard.winningTeam(); ok {
+		for i := range scoreboard {
+			if scoreboard[i].Team == team {
+				scoreboard[i].Placement = 1
+			} else {
+				scoreboard[i].Placement = max(scoreboard[i].Placement, 2)
+			}
+		}
+
+		return scoreboard
+	}
+
+	for i := range scoreboard {
+		if scoreboard[i].Placement == 0 {
+			scoreboard[i].Placement = 1
+		}
+	}
+
+	return scoreboard
+}
+
+// winningTeam is the team that won the round, unless the snakes did not play
+// in teams or the teams tied.
+func (s Scoreboard) winningTeam() (string, bool) {
+	lengths := make(map[string]int)
+	alive := make(map[string]bool)
+
+	for _, score := range s {
+		if score.Team == "" {
+			continue
+		}
+
+		lengths[score.Team] += score.Length
+
+		if score.Placement == 0 {
+			alive[score.Team] = true
+		}
+	}
+
+	if len(lengths) < 2 {
+		return "", false
+	}
+
+	if len(alive) == 1 {
+		for team := range alive {
+			return team, true
+		}
+	}
+
+	winner, tied := "", false
+
+	for team, length := range lengths {
+		if len(alive) > 1 && !alive[team] {
+			continue
+		}
+
+		switch {
+		case winner == "" || length > lengths[winner]:
+			winner, tied = team, false
+		case length == lengths[winner]:
+			tied = true
+		}
+	}
+
+	return winner, !tied
+}
+
+// Standings sorts the scores by placement, leaving the players still alive
+// at the top.
+func (s Scoreboard) Standings() []Score {
+	standings := make([]Score, len(s))
+	copy(standings, s)
+
+	sort.SliceStable(standings, func(a, b int) bool {
+		pa, pb := standings[a].Placement, standings[b].Placement
+
+		if pa == 0 || pb == 0 {
+			return pa == 0 && pb != 0
+		}
+
+		return pa < pb
+	})
+
+	return standings
+}
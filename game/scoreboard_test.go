@@ -0,0 +1,106 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScoreboard(t *testing.T) {
+	t.Run("should count the foods eaten and the kills", func(t *testing.T) {
+		world := newTestWorld(1, "1", "2")
+		scoreboard := NewScoreboard(world)
+
+		world.Tick = 1
+		world.Snakes[0].Body = append(world.Snakes[0].Body, BodyFragment{X: 13, Y: 9})
+		world.Snakes[1].Alive = false
+
+		scoreboard = scoreboard.Record(world, []Event{
+			{Type: EventAte, PlayerID: "1"},
+			{Type: EventDied, PlayerID: "2", Cause: CauseSnake, KillerID: "1"},
+		})
+
+		assert.Equal(t, Scoreboard{
+			{PlayerID: "1", Length: 4, FoodEaten: 1, Kills: 1, SurvivalTicks: 1},
+			{PlayerID: "2", Length: 3, SurvivalTicks: 1, Placement: 2, Cause: CauseSnake, KillerID: "1"},
+		}, scoreboard)
+	})
+
+	t.Run("should keep the score of a dead snake as it was when it died", func(t *testing.T) {
+		world := newTestWorld(1, "1", "2")
+		scoreboard := NewScoreboard(world)
+
+		world.Tick = 1
+		world.Snakes[1].Alive = false
+		scoreboard = scoreboard.Record(world, []Event{{Type: EventDied, PlayerID: "2", Cause: CauseWall}})
+
+		world.Tick = 2
+		scoreboard = scoreboard.Record(world, []Event{{Type: EventAte, PlayerID: "1"}})
+
+		assert.Equal(t, uint64(2), scoreboard[0].SurvivalTicks)
+		assert.Equal(t, uint64(1), scoreboard[1].SurvivalTicks)
+	})
+
+	t.Run("should place the longer of the snakes that died together first", func(t *testing.T) {
+		world := newTestWorld(1, "1", "2", "3")
+		scoreboard := NewScoreboard(world)
+
+		world, events := TimeOut(world)
+		world.Snakes[2].Body = append(world.Snakes[2].Body, BodyFragment{})
+		scoreboard = scoreboard.Record(world, events)
+
+		standings := scoreboard.Standings()
+		assert.Equal(t, "3", standings[0].PlayerID)
+		assert.Equal(t, 1, standings[0].Placement)
+		assert.Equal(t, 2, standings[1].Placement)
+		assert.Equal(t, 2, standings[2].Placement)
+		assert.Equal(t, CauseTimeout, standings[2].Cause)
+	})
+
+	t.Run("should list the snakes still alive first", func(t *testing.T) {
+		scoreboard := Scoreboard{
+			{PlayerID: "1", Placement: 2},
+			{PlayerID: "2"},
+		}
+
+		assert.Equal(t, "2", scoreboard.Standings()[0].PlayerID)
+	})
+
+	t.Run("should place the snake left alive first when the round is finished", func(t *testing.T) {
+		scoreboard := Scoreboard{
+			{PlayerID: "1", Placement: 2},
+			{PlayerID: "2"},
+		}
+
+		finished := scoreboard.Finish()
+
+		assert.Equal(t, 1, finished[1].Placement)
+		assert.Zero(t, scoreboard[1].Placement)
+	})
+
+	t.Run("should place the last team with snakes alive first", func(t *testing.T) {
+		scoreboard := Scoreboard{
+			{PlayerID: "1", Team: "red", Length: 9, Placement: 1},
+			{PlayerID: "2", Team: "blue", Length: 3},
+			{PlayerID: "3", Team: "red", Length: 9, Placement: 2},
+			{PlayerID: "4", Team: "blue", Length: 3, Placement: 3},
+		}
+
+		finished := scoreboard.Finish()
+
+		assert.Equal(t, []int{2, 1, 2, 1}, []int{finished[0].Placement, finished[1].Placement, finished[2].Placement, finished[3].Placement})
+	})
+
+	t.Run("should place the team of the greatest combined length first at timeout", func(t *testing.T) {
+		scoreboard := Scoreboard{
+			{PlayerID: "1", Team: "red", Length: 9, Placement: 1},
+			{PlayerID: "2", Team: "blue", Length: 6, Placement: 2},
+			{PlayerID: "3", Team: "red", Length: 3, Placement: 3},
+			{PlayerID: "4", Team: "blue", Length: 7, Placement: 2},
+		}
+
+		finished := scoreboard.Finish()
+
+		assert.Equal(t, []int{2, 1, 3, 1}, []int{finished[0].Placement, finished[1].Placement, finished[2].Placement, finished[3].Placement})
+	})
+}
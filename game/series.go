@@ -0,0 +1,121 @@
+package game
+
+import "sort"
+
+// SeriesScore is how many rounds of a series a player won, along with the
+// team the player played the last round in.
+type SeriesScore struct {
+	PlayerID string
+	Team     string
+	Points   int
+}
+
+// Series is a best of Length rounds. Every player placed first in a round
+// scores a point, and the series is won by the first player to win more
+// than half of the rounds. When the rounds run out with the lead shared,
+// more rounds are played until a single player leads. In teams, the series
+// is won by a team instead, teammates sharing the lead counting as one.
+// Like Scoreboard, it is a plain value: Record returns a new one.
+type Series struct {
+	Length int
+	Rounds int
+	Scores []SeriesScore
+}
+
+// NewSeries starts a best of length rounds, a single round unless length is
+// greater than one.
+func NewSeries(length int) Series {
+	return Series{
+		Length: max(length, 1),
+		Scores: make([]SeriesScore, 0),
+	}
+}
+
+// Record counts a round that ended with scoreboard.
+func (s Series) Record(scoreboard Scoreboard) Series {
+	scores := make([]SeriesScore, len(s.Scores))
+	copy(scores, s.Scores)
+
+	index := make(map[string]int, len(scores))
+	for i, score := range scores {
+		index[score.PlayerID] = i
+	}
+
+	for _, score := range scoreboard {
+		i, ok := index[score.PlayerID]
+		if !ok {
+			i = len(scores)
+			index[score.PlayerID] = i
+			scores = append(scores, SeriesScore{PlayerID: score.PlayerID})
+		}
+
+		scores[i].Team = score.Team
+
+		if score.Placement == 1 {
+			scores[i].Points++
+		}
+	}
+
+	s.Rounds++
+	s.Scores = scores
+
+	return s
+}
+
+// Standings sorts the scores by points, the most first.
+func (s Series) Standings() []SeriesScore {
+	standings := make([]SeriesScore, len(s.Scores))
+	copy(standings, s.Scores)
+
+	sort.SliceStable(standings, func(a, b int) bool {
+		return standings[a].Points > standings[b].Points
+	})
+
+	return standings
+}
+
+// InTeams tells whether the series is played in teams.
+func (s Series) InTeams() bool {
+	for _, score := range s.Scores {
+		if score.Team != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Winner is the player, or the team when the series is played in teams,
+// that won the series, if it is over.
+func (s Series) Winner() (string, bool) {
+	standings := s.Standings()
+	if len(standings) == 0 {
+		return "", false
+	}
+
+	inTeams := s.InTeams()
+	winner := func(score SeriesScore) string {
+		if inTeams {
+			return score.Team
+		}
+
+		return score.PlayerID
+	}
+
+	leader := standings[0]
+	if leader.Points == 0 {
+		return "", false
+	}
+
+	for _, score := range standings[1:] {
+		if score.Points == leader.Points && winner(score) != winner(leader) {
+			return "", false
+		}
+	}
+
+	if leader.Points > s.Length/2 || s.Rounds >= s.Length {
+		return winner(leader), true
+	}
+
+	return "", false
+}
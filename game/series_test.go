@@ -0,0 +1,106 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeries(t *testing.T) {
+	round := func(winners ...string) Scoreboard {
+		scoreboard := Scoreboard{{PlayerID: "1", Placement: 2}, {PlayerID: "2", Placement: 2}, {PlayerID: "3", Placement: 2}}
+
+		for i := range scoreboard {
+			for _, winner := range winners {
+				if scoreboard[i].PlayerID == winner {
+					scoreboard[i].Placement = 1
+				}
+			}
+		}
+
+		return scoreboard
+	}
+
+	t.Run("should be won by the first to win more than half of the rounds", func(t *testing.T) {
+		series := NewSeries(3).Record(round("1"))
+
+		_, over := series.Winner()
+		assert.False(t, over)
+
+		series = series.Record(round("2")).Record(round("1"))
+
+		winner, over := series.Winner()
+		assert.True(t, over)
+		assert.Equal(t, "1", winner)
+		assert.Equal(t, []SeriesScore{{PlayerID: "1", Points: 2}, {PlayerID: "2", Points: 1}, {PlayerID: "3"}}, series.Standings())
+	})
+
+	t.Run("should give a point to every player sharing the first place", func(t *testing.T) {
+		series := NewSeries(1).Record(round("1", "2"))
+
+		_, over := series.Winner()
+		assert.False(t, over)
+
+		winner, over := series.Record(round("2")).Winner()
+		assert.True(t, over)
+		assert.Equal(t, "2", winner)
+	})
+
+	t.Run("should go on while the lead is shared when the rounds run out", func(t *testing.T) {
+		series := NewSeries(5).Record(round("1")).Record(round("2")).Record(round("3")).Record(round("1")).Record(round("2"))
+
+		_, over := series.Winner()
+		assert.False(t, over)
+
+		winner, over := series.Record(round("1")).Winner()
+		assert.True(t, over)
+		assert.Equal(t, "1", winner)
+	})
+
+	t.Run("should play a single round unless longer", func(t *testing.T) {
+		winner, over := NewSeries(0).Record(round("3")).Winner()
+
+		assert.True(t, over)
+		assert.Equal(t, "3", winner)
+	})
+
+	t.Run("should be won by a team when played in teams", func(t *testing.T) {
+		// teamRound ends a round of two teams of two with only the last
+		// snake of team alive.
+		teamRound := func(team string) Scoreboard {
+			scoreboard := Scoreboard{
+				{PlayerID: "1", Team: "red", Length: 3, Placement: 3},
+				{PlayerID: "2", Team: "red", Length: 3, Placement: 3},
+				{PlayerID: "3", Team: "blue", Length: 3, Placement: 3},
+				{PlayerID: "4", Team: "blue", Length: 3, Placement: 3},
+			}
+
+			for i := range scoreboard {
+				if scoreboard[i].Team == team {
+					scoreboard[i].Placement = 0
+					break
+				}
+			}
+
+			return scoreboard.Finish()
+		}
+
+		series := NewSeries(3).Record(teamRound("red"))
+
+		_, over := series.Winner()
+		assert.False(t, over)
+		assert.True(t, series.InTeams())
+
+		series = series.Record(teamRound("blue"))
+
+		_, over = series.Winner()
+		assert.False(t, over)
+
+		series = series.Record(teamRound("red"))
+
+		winner, over := series.Winner()
+		assert.True(t, over)
+		assert.Equal(t, "red", winner)
+		assert.Equal(t, 3, series.Rounds)
+	})
+}
@@ -0,0 +1,187 @@
+package game
+
+import "golang.org/x/exp/slices"
+
+// keyframeInterval is how many ticks apart full snapshots are sent, so that a
+// client that missed a delta gets back in sync in at most this many ticks.
+const keyframeInterval = 54
+
+// Snapshot is what changed in the world during a tick. On keyframes it
+// carries the whole state instead: every body, every food and every item. In
+// a shrinking arena, Arena is set when it shrank and on keyframes, and
+// Warning when a shrink is announced.
+type Snapshot struct {
+	Seq          uint64
+	Keyframe     bool
+	Snakes       []SnakeDelta
+	Foods        []FoodDelta
+	Items        []Item
+	RemovedItems []uint64
+	Arena        *Arena
+	Warning      *ShrinkWarning
+}
+
+// SnakeDelta turns the previous body of a snake into the current one: the
+// Head fragments are prepended, in order, and Tail fragments are removed
+// from the end. On keyframes Body holds the whole body instead. Effects is
+// set when the effects acting on the snake changed and on keyframes.
+type SnakeDelta struct {
+	PlayerID string
+	Alive    bool
+	Head     []BodyFragment
+	Tail     int
+	Body     []BodyFragment
+	Effects  *[]Effect
+}
+
+type FoodDelta struct {
+	Index    int
+	Position foodPosition
+}
+
+func NewKeyframe(world World) Snapshot {
+	snapshot := Snapshot{
+		Seq:      world.Tick,
+		Keyframe: true,
+		Snakes:   make([]SnakeDelta, 0, len(world.Snakes)),
+		Foods:    make([]FoodDelta, 0, len(world.Foods)),
+		Items:    append(make([]Item, 0, len(world.Items)), world.Items...),
+	}
+
+	for _, snake := range world.Snakes {
+		effects := append(make([]Effect, 0, len(snake.Effects)), snake.Effects...)
+
+		snapshot.Snakes = append(snapshot.Snakes, SnakeDelta{
+			PlayerID: snake.PlayerID,
+			Alive:    snake.Alive,
+			Body:     snake.Body,
+			Effects:  &effects,
+		})
+	}
+
+	for i, food := range world.Foods {
+		snapshot.Foods = append(snapshot.Foods, FoodDelta{
+			Index:    i,
+			Position: food,
+		})
+	}
+
+	if world.Shrink.Enabled() {
+		arena := world.Arena()
+		snapshot.Arena = &arena
+	}
+
+	if warning, ok := world.NextShrink(); ok {
+		snapshot.Warning = &warning
+	}
+
+	return snapshot
+}
+
+// Diff describes how prev became next. Every keyframeInterval ticks it
+// returns a keyframe of next instead.
+func Diff(prev, next World) Snapshot {
+	if next.Tick%keyframeInterval == 0 {
+		return NewKeyframe(next)
+	}
+
+	snapshot := Snapshot{
+		Seq:    next.Tick,
+		Snakes: make([]SnakeDelta, 0, len(next.Snakes)),
+		Foods:  make([]FoodDelta, 0),
+	}
+
+	for _, snake := range next.Snakes {
+		prevSnake, _ := prev.GetSnake(snake.PlayerID)
+
+		if !snake.Alive && !prevSnake.Alive {
+			continue
+		}
+
+		head, tail := diffBody(prevSnake.Body, snake.Body)
+
+		delta := SnakeDelta{
+			PlayerID: snake.PlayerID,
+			Alive:    snake.Alive,
+			Head:     head,
+			Tail:     tail,
+		}
+
+		if !slices.Equal(prevSnake.Effects, snake.Effects) {
+			effects := append(make([]Effect, 0, len(snake.Effects)), snake.Effects...)
+			delta.Effects = &effects
+		}
+
+		snapshot.Snakes = append(snapshot.Snakes, delta)
+	}
+
+	for i, food := range next.Foods {
+		if i < len(prev.Foods) && prev.Foods[i] == food {
+			continue
+		}
+
+		snapshot.Foods = append(snapshot.Foods, FoodDelta{
+			Index:    i,
+			Position: food,
+		})
+	}
+
+	snapshot.Items, snapshot.RemovedItems = diffItems(prev.Items, next.Items)
+
+	if arena := next.Arena(); arena != prev.Arena() {
+		snapshot.Arena = &arena
+	}
+
+	if warning, ok := next.NextShrink(); ok {
+		snapshot.Warning = &warning
+	}
+
+	return snapshot
+}
+
+// diffItems lists the items of next that prev did not have and the IDs of
+// the items of prev that next no longer has.
+func diffItems(prev, next []Item) ([]Item, []uint64) {
+	added := make([]Item, 0)
+	removed := make([]uint64, 0)
+
+	for _, item := range next {
+		if !slices.Contains(prev, item) {
+			added = append(added, item)
+		}
+	}
+
+	for _, item := range prev {
+		if !slices.Contains(next, item) {
+			removed = append(removed, item.ID)
+		}
+	}
+
+	return added, removed
+}
+
+// diffBody finds the fewest fragments to prepend to prev, and how many to
+// drop from its end, to get next.
+func diffBody(prev, next []BodyFragment) ([]BodyFragment, int) {
+	for k := 0; k <= len(next); k++ {
+		kept := next[k:]
+
+		if len(kept) > len(prev) || !isPrefix(kept, prev) {
+			continue
+		}
+
+		return next[:k], len(prev) - len(kept)
+	}
+
+	return next, len(prev)
+}
+
+func isPrefix(prefix, body []BodyFragment) bool {
+	for i := range prefix {
+		if prefix[i] != body[i] {
+			return false
+		}
+	}
+
+	return true
+}
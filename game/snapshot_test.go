@@ -0,0 +1,97 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func applySnapshot(bodies map[string][]BodyFragment, foods map[int]foodPosition, snapshot Snapshot) {
+	for _, snake := range snapshot.Snakes {
+		if snapshot.Keyframe {
+			bodies[snake.PlayerID] = snake.Body
+			continue
+		}
+
+		body := bodies[snake.PlayerID]
+		body = append(append([]BodyFragment{}, snake.Head...), body[:len(body)-snake.Tail]...)
+		bodies[snake.PlayerID] = body
+	}
+
+	for _, food := range snapshot.Foods {
+		foods[food.Index] = food.Position
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t.Run("should rebuild every body and food from the deltas", func(t *testing.T) {
+		world := newTestWorld(3, "1", "2")
+		world.Foods[0] = foodPosition{X: 20, Y: 9}
+
+		bodies := make(map[string][]BodyFragment)
+		foods := make(map[int]foodPosition)
+		applySnapshot(bodies, foods, NewKeyframe(world))
+
+		inputs := [][]Input{
+			{{PlayerID: "2", Move: MoveUp}},
+			{},
+			{{PlayerID: "2", Move: MoveRight}},
+			{},
+		}
+
+		for i := 0; i < 120; i++ {
+			prev := world
+			world, _ = Step(world, inputs[i%len(inputs)])
+
+			snapshot := Diff(prev, world)
+			assert.Equal(t, world.Tick, snapshot.Seq)
+			assert.Equal(t, world.Tick%keyframeInterval == 0, snapshot.Keyframe)
+
+			applySnapshot(bodies, foods, snapshot)
+
+			for _, snake := range world.Snakes {
+				if snake.Alive {
+					assert.Equal(t, snake.Body, bodies[snake.PlayerID])
+				}
+			}
+
+			for i, food := range world.Foods {
+				assert.Equal(t, food, foods[i])
+			}
+		}
+	})
+
+	t.Run("should only send the new head and the removed tail", func(t *testing.T) {
+		prev := newTestWorld(1, "1")
+		next, _ := Step(prev, nil)
+
+		snapshot := Diff(prev, next)
+
+		assert.Equal(t, []SnakeDelta{{
+			PlayerID: "1",
+			Alive:    true,
+			Head:     []BodyFragment{{X: 17, Y: 9}},
+			Tail:     1,
+		}}, snapshot.Snakes)
+		assert.Empty(t, snapshot.Foods)
+	})
+
+	t.Run("should send the items added and removed and the effects that changed", func(t *testing.T) {
+		prev := newTestWorld(1, "1")
+		prev.ItemRules = DefaultItemRules(0, 1)
+		prev.Items = []Item{{ID: 1, Kind: ItemGhost, Position: Cell{X: 17, Y: 9}, ExpiresAt: 50}}
+
+		next, _ := Step(prev, nil)
+		next.Items = append(next.Items, Item{ID: 2, Kind: ItemSpeed, Position: Cell{X: 3, Y: 3}, ExpiresAt: 60})
+
+		snapshot := Diff(prev, next)
+
+		assert.Equal(t, []Item{{ID: 2, Kind: ItemSpeed, Position: Cell{X: 3, Y: 3}, ExpiresAt: 60}}, snapshot.Items)
+		assert.Equal(t, []uint64{1}, snapshot.RemovedItems)
+		assert.Equal(t, &[]Effect{{Kind: ItemGhost, Until: 6}}, snapshot.Snakes[0].Effects)
+
+		following, _ := Step(next, nil)
+
+		assert.Nil(t, Diff(next, following).Snakes[0].Effects)
+	})
+}
@@ -0,0 +1,247 @@
+package game
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/slices"
+)
+
+// spawnClearance is how many free tiles are kept ahead of a snake when it
+// spawns, so no one dies on the first ticks of a round.
+const spawnClearance = 3
+
+// Spawn is where a snake starts a round and where it is heading to.
+type Spawn struct {
+	Body   []BodyFragment
+	Moving movement
+}
+
+// AllocateSpawns picks a spawn for each of n snakes of length fragments.
+//
+// Every spawn lies on free tiles of the largest area of the map the snakes
+// can move around in, facing the center of the map with spawnClearance free
+// tiles ahead that no other snake spawns on. The first snake spawns as close
+// as it can to the first quarter of the map and each of the others as far as
+// it can from the snakes placed before it. Ties are broken by the position
+// of the head, row by row, so the same map always gets the same spawns.
+//
+// When the map has no room for every snake, the spawns found so far are
+// returned along with ErrSpawnBlocked. A negative n or a length below one
+// is refused with ErrSpawnCount or ErrSpawnLength.
+func AllocateSpawns(_map Map, n, length int) ([]Spawn, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("%w: %d", ErrSpawnCount, n)
+	}
+
+	if length < 1 {
+		return nil, fmt.Errorf("%w: %d", ErrSpawnLength, length)
+	}
+
+	spawns := make([]Spawn, 0, n)
+
+	if n == 0 {
+		return spawns, nil
+	}
+
+	area := _map.largestArea()
+	taken := make(map[BodyFragment]bool)
+	heads := make([]BodyFragment, 0, n)
+
+	home := BodyFragment{X: _map.Tiles.Horizontal / 4, Y: _map.Tiles.Vertical / 4}
+
+	for len(spawns) < n {
+		var (
+			best      Spawn
+			bestScore = -1
+		)
+
+		for y := 0; y < _map.Tiles.Vertical; y++ {
+			for x := 0; x < _map.Tiles.Horizontal; x++ {
+				head := BodyFragment{X: x, Y: y}
+				if !area[head] || taken[head] {
+					continue
+				}
+
+				var score int
+
+				if len(heads) == 0 {
+					score = _map.Tiles.Horizontal + _map.Tiles.Vertical - absInt(head.X-home.X) - absInt(head.Y-home.Y)
+				} else {
+					score = _map.Tiles.Horizontal + _map.Tiles.Vertical
+					for _, other := range heads {
+						score = min(score, _map.distance(head, other))
+					}
+				}
+
+				if score <= bestScore {
+					continue
+				}
+
+				if spawn, ok := _map.spawnAt(head, length, area, taken); ok {
+					best, bestScore = spawn, score
+				}
+			}
+		}
+
+		if bestScore < 0 {
+			return spawns, fmt.Errorf("%w: spawn %d", ErrSpawnBlocked, len(spawns)+1)
+		}
+
+		for _, bodyFragment := range best.Body {
+			taken[bodyFragment] = true
+		}
+
+		for _, bodyFragment := range _map.lane(best.Body[0], best.Moving, area) {
+			taken[bodyFragment] = true
+		}
+
+		spawns = append(spawns, best)
+		heads = append(heads, best.Body[0])
+	}
+
+	return spawns, nil
+}
+
+// spawnAt looks for a heading for a snake with its head at head, trying the
+// ones facing the center of the map first.
+func (m Map) spawnAt(head BodyFragment, length int, area, taken map[BodyFragment]bool) (Spawn, bool) {
+	for _, mv := range m.headings(head) {
+		body := make([]BodyFragment, 0, length)
+		bodyFragment := head
+
+		for i := 0; i < length; i++ {
+			if !m.Tiles.contains(bodyFragment) || !area[bodyFragment] || taken[bodyFragment] {
+				break
+			}
+
+			body = append(body, bodyFragment)
+			bodyFragment = bodyFragment.next(opposite(mv))
+		}
+
+		if len(body) < length {
+			continue
+		}
+
+		lane := m.lane(head, mv, area)
+		if len(lane) < spawnClearance {
+			continue
+		}
+
+		free := true
+
+		for _, tile := range lane {
+			if taken[tile] || slices.Contains(body, tile) {
+				free = false
+				break
+			}
+		}
+
+		if free {
+			return Spawn{Body: body, Moving: mv}, true
+		}
+	}
+
+	return Spawn{}, false
+}
+
+// headings sorts the movements by how much they take head towards the
+// center of the map.
+func (m Map) headings(head BodyFragment) []movement {
+	dx := m.Tiles.Horizontal/2 - head.X
+	dy := m.Tiles.Vertical/2 - head.Y
+
+	horizontal, vertical := MoveRight, MoveDown
+	if dx < 0 {
+		horizontal, dx = MoveLeft, -dx
+	}
+
+	if dy < 0 {
+		vertical, dy = MoveUp, -dy
+	}
+
+	if dy > dx {
+		return []movement{vertical, horizontal, opposite(horizontal), opposite(vertical)}
+	}
+
+	return []movement{horizontal, vertical, opposite(vertical), opposite(horizontal)}
+}
+
+// lane lists the spawnClearance tiles ahead of head, stopping at the first
+// one out of area.
+func (m Map) lane(head BodyFragment, mv movement, area map[BodyFragment]bool) []BodyFragment {
+	lane := make([]BodyFragment, 0, spawnClearance)
+	tile := head
+
+	for i := 0; i < spawnClearance; i++ {
+		next, ok := m.neighbor(tile, mv)
+		if !ok || !area[next] {
+			break
+		}
+
+		lane = append(lane, next)
+		tile = next
+	}
+
+	return lane
+}
+
+// largestArea is the largest set of free tiles connected to each other.
+func (m Map) largestArea() map[BodyFragment]bool {
+	walls := m.wallSet()
+	seen := make(map[BodyFragment]bool)
+	largest := make(map[BodyFragment]bool)
+
+	for y := 0; y < m.Tiles.Vertical; y++ {
+		for x := 0; x < m.Tiles.Horizontal; x++ {
+			tile := BodyFragment{X: x, Y: y}
+			if walls[tile] || seen[tile] {
+				continue
+			}
+
+			area := m.reachable(tile)
+			for reached := range area {
+				seen[reached] = true
+			}
+
+			if len(area) > len(largest) {
+				largest = area
+			}
+		}
+	}
+
+	return largest
+}
+
+// distance is the number of moves between a and b, going around the edges
+// of the map when they wrap.
+func (m Map) distance(a, b BodyFragment) int {
+	dx, dy := absInt(a.X-b.X), absInt(a.Y-b.Y)
+
+	if m.Edges != EdgesWall {
+		dx = min(dx, m.Tiles.Horizontal-dx)
+		dy = min(dy, m.Tiles.Vertical-dy)
+	}
+
+	return dx + dy
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+func opposite(mv movement) movement {
+	switch mv {
+	case MoveUp:
+		return MoveDown
+	case MoveDown:
+		return MoveUp
+	case MoveLeft:
+		return MoveRight
+	default:
+		return MoveLeft
+	}
+}
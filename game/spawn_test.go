@@ -0,0 +1,78 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllocateSpawns(t *testing.T) {
+	t.Run("should spawn the first snake at the first quarter of the map facing its center", func(t *testing.T) {
+		spawns, err := AllocateSpawns(Map{Tiles: Tiles{Horizontal: 64, Vertical: 36}}, 1, 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Spawn{{
+			Body:   []BodyFragment{{X: 16, Y: 9}, {X: 15, Y: 9}, {X: 14, Y: 9}},
+			Moving: MoveRight,
+		}}, spawns)
+	})
+
+	t.Run("should refuse a negative number of snakes or snakes shorter than one fragment", func(t *testing.T) {
+		_map := Map{Tiles: Tiles{Horizontal: 64, Vertical: 36}}
+
+		_, err := AllocateSpawns(_map, -1, 3)
+		assert.ErrorIs(t, err, ErrSpawnCount)
+
+		for _, length := range []int{0, -2} {
+			_, err = AllocateSpawns(_map, 2, length)
+			assert.ErrorIs(t, err, ErrSpawnLength)
+		}
+	})
+
+	t.Run("should keep the spawns and the tiles ahead of them apart on a small map", func(t *testing.T) {
+		_map := Map{Tiles: Tiles{Horizontal: 10, Vertical: 8}, Edges: EdgesWall}
+
+		spawns, err := AllocateSpawns(_map, 9, 3)
+		assert.NoError(t, err)
+		assert.Len(t, spawns, 9)
+
+		taken := make(map[BodyFragment]bool)
+
+		for _, spawn := range spawns {
+			tiles := append(spawn.Body, _map.lane(spawn.Body[0], spawn.Moving, _map.largestArea())...)
+			assert.Len(t, tiles, 3+spawnClearance)
+
+			for _, tile := range tiles {
+				assert.True(t, _map.Tiles.contains(tile))
+				assert.False(t, taken[tile], "%v is taken twice", tile)
+				taken[tile] = true
+			}
+		}
+	})
+
+	t.Run("should spawn the snakes where they can reach each other", func(t *testing.T) {
+		rows := make([]string, 18)
+		for y := range rows {
+			rows[y] = strings.Repeat(".", 8) + "#" + strings.Repeat(".", 23)
+		}
+
+		_map, _ := NewMapFromGrid(EdgesWall, rows)
+
+		spawns, err := AllocateSpawns(_map, 4, 3)
+		assert.NoError(t, err)
+
+		for _, spawn := range spawns {
+			assert.Greater(t, spawn.Body[0].X, 8)
+		}
+	})
+
+	t.Run("should give the same spawns for the same map", func(t *testing.T) {
+		_map, _ := GetMapDefinition("pillars")
+
+		spawns1, _ := AllocateSpawns(_map, 9, 5)
+		spawns2, _ := AllocateSpawns(_map, 9, 5)
+
+		assert.Equal(t, spawns1, spawns2)
+	})
+}
@@ -0,0 +1,290 @@
+package game
+
+import "golang.org/x/exp/slices"
+
+// Step advances the world by one tick. It runs the simulation phases in the
+// order documented on match.loop (input, move, wrap, eat, grow, shrink,
+// collide, boost and items) and returns the new world along with what
+// happened during the tick. The given world is left untouched.
+func Step(world World, inputs []Input) (World, []Event) {
+	world = world.clone()
+	world.Tick++
+	world.expireEffects()
+
+	events := make([]Event, 0)
+
+	events = world.applyInputs(inputs, events)
+	world.move(false)
+	world.wrap()
+	events = world.eat(events)
+	world.grow(false)
+	world.shrink()
+	events = world.collide(events, false)
+
+	// Boosted snakes move a second tile, going through the same phases
+	// again on their own.
+	if world.boosting() {
+		world.move(true)
+		world.wrap()
+		events = world.eat(events)
+		world.grow(true)
+		events = world.collide(events, true)
+	}
+
+	world.spawnItems()
+
+	return world, events
+}
+
+func (w *World) applyInputs(inputs []Input, events []Event) []Event {
+	for _, input := range inputs {
+		for i := range w.Snakes {
+			snake := &w.Snakes[i]
+
+			if snake.PlayerID != input.PlayerID || !snake.Alive {
+				continue
+			}
+
+			if input.Control == nil {
+				if snake.Control != ControlPlayer {
+					continue
+				}
+
+				if snake.hasEffect(ItemReverse) {
+					snake.addMovement(opposite(input.Move))
+				} else {
+					snake.addMovement(input.Move)
+				}
+
+				continue
+			}
+
+			snake.Control = *input.Control
+			snake.Movements = nil
+
+			if snake.Control == ControlLeft {
+				snake.Alive = false
+
+				events = append(events, Event{
+					Type:     EventDied,
+					PlayerID: snake.PlayerID,
+					Cause:    CauseLeft,
+				})
+			}
+		}
+	}
+
+	return events
+}
+
+func (s *Snake) addMovement(mv movement) {
+	nextMovement := s.Moving
+	if len(s.Movements) > 0 {
+		nextMovement = s.Movements[0]
+	}
+
+	if slices.Contains(horizontalMovements, nextMovement) && slices.Contains(horizontalMovements, mv) {
+		return
+	}
+
+	if slices.Contains(VerticalMovements, nextMovement) && slices.Contains(VerticalMovements, mv) {
+		return
+	}
+
+	s.Movements = append(s.Movements, mv)
+}
+
+// move advances the alive snakes one tile, only the boosted ones when boost
+// is set.
+func (w *World) move(boost bool) {
+	for i := range w.Snakes {
+		snake := &w.Snakes[i]
+
+		if !snake.Alive || snake.Control == ControlFrozen || (boost && !snake.hasEffect(ItemSpeed)) {
+			continue
+		}
+
+		if snake.Control == ControlAutopilot {
+			w.steer(snake)
+		}
+
+		if len(snake.Movements) > 0 {
+			snake.Moving, snake.Movements = snake.Movements[0], snake.Movements[1:]
+		}
+
+		head := snake.Body[0].next(snake.Moving)
+
+		snake.LastTail = snake.Body[len(snake.Body)-1]
+		snake.Body = append([]BodyFragment{head}, snake.Body[:len(snake.Body)-1]...)
+	}
+}
+
+// steer keeps an autopiloted snake going straight while the tile ahead is
+// free, and otherwise turns it to the first free side, checking up or left
+// before down or right so the outcome does not depend on anything but the
+// world.
+func (w *World) steer(snake *Snake) {
+	options := []movement{snake.Moving, MoveUp, MoveDown}
+	if slices.Contains(VerticalMovements, snake.Moving) {
+		options = []movement{snake.Moving, MoveLeft, MoveRight}
+	}
+
+	occupied := w.Map.wallSet()
+	for _, other := range w.Snakes {
+		if !other.Alive {
+			continue
+		}
+
+		for _, bodyFragment := range other.Body {
+			occupied[bodyFragment] = true
+		}
+	}
+
+	head := snake.Body[0]
+
+	for _, mv := range options {
+		next := head.next(mv)
+
+		if w.Map.Edges == EdgesWall && !w.Map.Tiles.contains(next) {
+			continue
+		}
+
+		if !occupied[w.Map.Tiles.wrap(next)] {
+			snake.Moving = mv
+			return
+		}
+	}
+}
+
+func (w *World) wrap() {
+	if w.Map.Edges == EdgesWall {
+		return
+	}
+
+	for i := range w.Snakes {
+		snake := &w.Snakes[i]
+
+		if !snake.Alive {
+			continue
+		}
+
+		snake.Body[0] = w.Map.Tiles.wrap(snake.Body[0])
+	}
+}
+
+func (bf BodyFragment) next(mv movement) BodyFragment {
+	switch mv {
+	case MoveRight:
+		bf.X += 1
+	case MoveLeft:
+		bf.X -= 1
+	case MoveUp:
+		bf.Y -= 1
+	case MoveDown:
+		bf.Y += 1
+	}
+
+	return bf
+}
+
+func (t Tiles) contains(bf BodyFragment) bool {
+	return bf.X >= 0 && bf.X < t.Horizontal && bf.Y >= 0 && bf.Y < t.Vertical
+}
+
+// wrap moves a fragment that left the map to the opposite edge.
+func (t Tiles) wrap(bf BodyFragment) BodyFragment {
+	if bf.X >= t.Horizontal {
+		bf.X = 0
+	}
+
+	if bf.X < 0 {
+		bf.X = t.Horizontal - 1
+	}
+
+	if bf.Y >= t.Vertical {
+		bf.Y = 0
+	}
+
+	if bf.Y < 0 {
+		bf.Y = t.Vertical - 1
+	}
+
+	return bf
+}
+
+func (w *World) eat(events []Event) []Event {
+	for i := range w.Foods {
+		for j := range w.Snakes {
+			snake := &w.Snakes[j]
+
+			if !snake.Alive {
+				continue
+			}
+
+			head := snake.Body[0]
+
+			if head.X != w.Foods[i].X || head.Y != w.Foods[i].Y {
+				continue
+			}
+
+			snake.ToIncrease += 1
+
+			if position, ok := w.freePosition(); ok {
+				w.Foods[i] = position
+			}
+
+			events = append(events, Event{
+				Type:     EventAte,
+				PlayerID: snake.PlayerID,
+				Food:     i,
+			})
+
+			break
+		}
+	}
+
+	return w.pickItems(events)
+}
+
+// grow makes the alive snakes that have eaten grow by their last tail, only
+// the boosted ones when boost is set.
+func (w *World) grow(boost bool) {
+	for i := range w.Snakes {
+		snake := &w.Snakes[i]
+
+		if !snake.Alive || (boost && !snake.hasEffect(ItemSpeed)) {
+			continue
+		}
+
+		if snake.ToIncrease > 0 {
+			snake.ToIncrease -= 1
+			snake.Body = append(snake.Body, snake.LastTail)
+		}
+	}
+}
+
+// TimeOut ends a round that ran out of time, killing the snakes still alive.
+// Like Step, it leaves the given world untouched.
+func TimeOut(world World) (World, []Event) {
+	world = world.clone()
+	events := make([]Event, 0)
+
+	for i := range world.Snakes {
+		snake := &world.Snakes[i]
+
+		if !snake.Alive {
+			continue
+		}
+
+		snake.Alive = false
+		snake.Movements = nil
+
+		events = append(events, Event{
+			Type:     EventDied,
+			PlayerID: snake.PlayerID,
+			Cause:    CauseTimeout,
+		})
+	}
+
+	return world, events
+}
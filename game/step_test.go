@@ -0,0 +1,339 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/Maycon-Santos/go-snake-backend/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRules = Rules{
+	Map:        Map{Tiles: Tiles{Horizontal: 64, Vertical: 36}},
+	FoodsLimit: 1,
+}
+
+func newTestWorld(seed int64, playerIDs ...string) World {
+	return NewWorld(seed, testRules, playerIDs)
+}
+
+func TestStep(t *testing.T) {
+	t.Run("should produce the same world from the same seed and inputs", func(t *testing.T) {
+		inputs := [][]Input{
+			{{PlayerID: "1", Move: MoveUp}},
+			{},
+			{{PlayerID: "2", Move: MoveDown}, {PlayerID: "1", Move: MoveLeft}},
+			{},
+		}
+
+		run := func() (World, []Event) {
+			world := newTestWorld(42, "1", "2")
+			events := make([]Event, 0)
+
+			for i := 0; i < 200; i++ {
+				var tickEvents []Event
+				world, tickEvents = Step(world, inputs[i%len(inputs)])
+				events = append(events, tickEvents...)
+			}
+
+			return world, events
+		}
+
+		world1, events1 := run()
+		world2, events2 := run()
+
+		assert.Equal(t, world1, world2)
+		assert.Equal(t, events1, events2)
+	})
+
+	t.Run("should not change the given world", func(t *testing.T) {
+		world := newTestWorld(1, "1")
+		before := world.clone()
+
+		Step(world, []Input{{PlayerID: "1", Move: MoveUp}})
+
+		assert.Equal(t, before, world)
+	})
+
+	t.Run("should move the snake towards its direction", func(t *testing.T) {
+		world := newTestWorld(1, "1")
+
+		world, _ = Step(world, nil)
+		assert.Equal(t, BodyFragment{X: 17, Y: 9}, world.Snakes[0].Body[0])
+
+		world, _ = Step(world, []Input{{PlayerID: "1", Move: MoveDown}})
+		assert.Equal(t, BodyFragment{X: 17, Y: 10}, world.Snakes[0].Body[0])
+		assert.Len(t, world.Snakes[0].Body, 3)
+	})
+
+	t.Run("should ignore a movement to the opposite direction", func(t *testing.T) {
+		world := newTestWorld(1, "1")
+
+		world, _ = Step(world, []Input{{PlayerID: "1", Move: MoveLeft}})
+
+		assert.Equal(t, BodyFragment{X: 17, Y: 9}, world.Snakes[0].Body[0])
+	})
+
+	t.Run("should wrap the snake around the edges of the map", func(t *testing.T) {
+		world := newTestWorld(1, "1")
+		world.Snakes[0].Body = []BodyFragment{{X: 63, Y: 0}, {X: 62, Y: 0}, {X: 61, Y: 0}}
+
+		world, _ = Step(world, nil)
+
+		assert.Equal(t, BodyFragment{X: 0, Y: 0}, world.Snakes[0].Body[0])
+	})
+
+	t.Run("should grow the snake that eats a food and summon it again", func(t *testing.T) {
+		world := newTestWorld(1, "1")
+		world.Foods[0] = foodPosition{X: 17, Y: 9}
+
+		world, events := Step(world, nil)
+
+		assert.Equal(t, []Event{{Type: EventAte, PlayerID: "1", Food: 0}}, events)
+		assert.Len(t, world.Snakes[0].Body, 4)
+		assert.NotEqual(t, foodPosition{X: 17, Y: 9}, world.Foods[0])
+	})
+
+	t.Run("should kill the snake whose head hits a body", func(t *testing.T) {
+		world := newTestWorld(1, "1", "2")
+		world.Snakes[1].Body = []BodyFragment{{X: 17, Y: 10}, {X: 17, Y: 9}, {X: 17, Y: 8}}
+		world.Snakes[1].Moving = MoveDown
+
+		world, events := Step(world, nil)
+
+		assert.False(t, world.Snakes[0].Alive)
+		assert.True(t, world.Snakes[1].Alive)
+		assert.Equal(t, []Event{{Type: EventDied, PlayerID: "1", Cause: CauseSnake, KillerID: "2"}}, events)
+		assert.True(t, world.HasAliveSnakes())
+	})
+
+	t.Run("should keep a frozen snake in place and ignore its moves", func(t *testing.T) {
+		world := newTestWorld(1, "1")
+
+		world, _ = Step(world, []Input{{PlayerID: "1", Control: utils.Ptr(ControlFrozen)}})
+		world, _ = Step(world, []Input{{PlayerID: "1", Move: MoveDown}})
+
+		assert.Equal(t, BodyFragment{X: 16, Y: 9}, world.Snakes[0].Body[0])
+		assert.Empty(t, world.Snakes[0].Movements)
+	})
+
+	t.Run("should turn an autopiloted snake away from a body ahead", func(t *testing.T) {
+		world := newTestWorld(1, "1", "2")
+		world.Snakes[1].Body = []BodyFragment{{X: 17, Y: 20}, {X: 17, Y: 21}, {X: 17, Y: 9}}
+		world.Snakes[1].Moving = MoveUp
+
+		world, _ = Step(world, []Input{{PlayerID: "1", Control: utils.Ptr(ControlAutopilot)}})
+
+		assert.True(t, world.Snakes[0].Alive)
+		assert.Equal(t, BodyFragment{X: 16, Y: 8}, world.Snakes[0].Body[0])
+	})
+
+	t.Run("should kill the snake of a player that left", func(t *testing.T) {
+		world := newTestWorld(1, "1")
+
+		world, events := Step(world, []Input{{PlayerID: "1", Control: utils.Ptr(ControlLeft)}})
+
+		assert.False(t, world.HasAliveSnakes())
+		assert.Equal(t, []Event{{Type: EventDied, PlayerID: "1", Cause: CauseLeft}}, events)
+	})
+
+	t.Run("should kill the snake that leaves a walled map", func(t *testing.T) {
+		world := newTestWorld(1, "1")
+		world.Map.Edges = EdgesWall
+		world.Snakes[0].Body = []BodyFragment{{X: 63, Y: 0}, {X: 62, Y: 0}, {X: 61, Y: 0}}
+
+		world, events := Step(world, nil)
+
+		assert.False(t, world.Snakes[0].Alive)
+		assert.Equal(t, []Event{{Type: EventDied, PlayerID: "1", Cause: CauseWall}}, events)
+	})
+
+	t.Run("should lay out snakes of the initial length", func(t *testing.T) {
+		rules := testRules
+		rules.InitialLength = 5
+
+		world := NewWorld(1, rules, []string{"1", "2"})
+
+		assert.Len(t, world.Snakes[0].Body, 5)
+		assert.Len(t, world.Snakes[1].Body, 5)
+		assert.Equal(t, BodyFragment{X: 16, Y: 9}, world.Snakes[0].Body[0])
+		assert.Equal(t, BodyFragment{X: 48, Y: 27}, world.Snakes[1].Body[0])
+	})
+
+	t.Run("should start a snake there is no room for dead", func(t *testing.T) {
+		rules := testRules
+		rules.Map = Map{Tiles: Tiles{Horizontal: 6, Vertical: 1}, Edges: EdgesWall}
+
+		world := NewWorld(1, rules, []string{"1", "2"})
+
+		assert.True(t, world.Snakes[0].Alive)
+		assert.False(t, world.Snakes[1].Alive)
+		assert.Empty(t, world.Snakes[1].Body)
+	})
+
+	t.Run("should blame the snake that runs into itself", func(t *testing.T) {
+		world := newTestWorld(1, "1")
+		world.Snakes[0].Body = []BodyFragment{{X: 10, Y: 10}, {X: 11, Y: 10}, {X: 11, Y: 11}, {X: 10, Y: 11}, {X: 9, Y: 11}}
+		world.Snakes[0].Moving = MoveDown
+
+		_, events := Step(world, nil)
+
+		assert.Equal(t, []Event{{Type: EventDied, PlayerID: "1", Cause: CauseSelf}}, events)
+	})
+
+	t.Run("should kill the snakes still alive when the time is up", func(t *testing.T) {
+		world := newTestWorld(1, "1", "2")
+		world.Snakes[1].Alive = false
+
+		world, events := TimeOut(world)
+
+		assert.False(t, world.HasAliveSnakes())
+		assert.Equal(t, []Event{{Type: EventDied, PlayerID: "1", Cause: CauseTimeout}}, events)
+	})
+
+	t.Run("should kill the snake whose head hits a wall", func(t *testing.T) {
+		world := newTestWorld(1, "1")
+		world.Map.Walls = []Cell{{X: 17, Y: 9}}
+
+		world, events := Step(world, nil)
+
+		assert.False(t, world.Snakes[0].Alive)
+		assert.Equal(t, []Event{{Type: EventDied, PlayerID: "1", Cause: CauseWall}}, events)
+	})
+}
+
+func TestCollide(t *testing.T) {
+	// headOn lays out two snakes moving into each other along a row: the
+	// first, of firstLength fragments, heading right with its head at x and
+	// the second, of secondLength, heading left with its head at x+gap.
+	headOn := func(collisions CollisionRules, x, gap, firstLength, secondLength int) World {
+		world := newTestWorld(1, "1", "2")
+		world.Collisions = collisions
+		world.Foods = nil
+
+		world.Snakes[0].Body = nil
+		for i := 0; i < firstLength; i++ {
+			world.Snakes[0].Body = append(world.Snakes[0].Body, BodyFragment{X: x - i, Y: 30})
+		}
+		world.Snakes[0].Moving = MoveRight
+
+		world.Snakes[1].Body = nil
+		for i := 0; i < secondLength; i++ {
+			world.Snakes[1].Body = append(world.Snakes[1].Body, BodyFragment{X: x + gap + i, Y: 30})
+		}
+		world.Snakes[1].Moving = MoveLeft
+
+		return world
+	}
+
+	t.Run("should kill both snakes whose heads meet", func(t *testing.T) {
+		world, events := Step(headOn(CollisionRules{}, 10, 2, 3, 5), nil)
+
+		assert.False(t, world.Snakes[0].Alive)
+		assert.False(t, world.Snakes[1].Alive)
+		assert.Equal(t, []Event{
+			{Type: EventDied, PlayerID: "1", Cause: CauseSnake, KillerID: "2"},
+			{Type: EventDied, PlayerID: "2", Cause: CauseSnake, KillerID: "1"},
+		}, events)
+	})
+
+	t.Run("should let the longer snake win a head-on collision", func(t *testing.T) {
+		world, events := Step(headOn(CollisionRules{HeadOn: HeadOnLongerWins}, 10, 2, 3, 5), nil)
+
+		assert.False(t, world.Snakes[0].Alive)
+		assert.True(t, world.Snakes[1].Alive)
+		assert.Equal(t, []Event{{Type: EventDied, PlayerID: "1", Cause: CauseSnake, KillerID: "2"}}, events)
+	})
+
+	t.Run("should let the longer snake win when the heads swap tiles", func(t *testing.T) {
+		world, events := Step(headOn(CollisionRules{HeadOn: HeadOnLongerWins}, 10, 1, 5, 3), nil)
+
+		assert.True(t, world.Snakes[0].Alive)
+		assert.False(t, world.Snakes[1].Alive)
+		assert.Equal(t, []Event{{Type: EventDied, PlayerID: "2", Cause: CauseSnake, KillerID: "1"}}, events)
+	})
+
+	t.Run("should kill both snakes of the same length even if the longer wins", func(t *testing.T) {
+		world, _ := Step(headOn(CollisionRules{HeadOn: HeadOnLongerWins}, 10, 2, 4, 4), nil)
+
+		assert.False(t, world.Snakes[0].Alive)
+		assert.False(t, world.Snakes[1].Alive)
+	})
+
+	t.Run("should not depend on the order of the snakes", func(t *testing.T) {
+		world := headOn(CollisionRules{HeadOn: HeadOnLongerWins}, 10, 2, 3, 5)
+		world.Snakes[0], world.Snakes[1] = world.Snakes[1], world.Snakes[0]
+
+		world, events := Step(world, nil)
+
+		assert.True(t, world.Snakes[0].Alive)
+		assert.False(t, world.Snakes[1].Alive)
+		assert.Equal(t, []Event{{Type: EventDied, PlayerID: "1", Cause: CauseSnake, KillerID: "2"}}, events)
+	})
+
+	// chase puts the head of the first snake right behind the tail of the
+	// second, both heading right.
+	chase := func(collisions CollisionRules) World {
+		world := newTestWorld(1, "1", "2")
+		world.Collisions = collisions
+		world.Foods = nil
+		world.Snakes[0].Body = []BodyFragment{{X: 10, Y: 30}, {X: 9, Y: 30}, {X: 8, Y: 30}}
+		world.Snakes[0].Moving = MoveRight
+		world.Snakes[1].Body = []BodyFragment{{X: 13, Y: 30}, {X: 12, Y: 30}, {X: 11, Y: 30}}
+		world.Snakes[1].Moving = MoveRight
+
+		return world
+	}
+
+	t.Run("should kill a snake moving into a tail that is leaving", func(t *testing.T) {
+		world, events := Step(chase(CollisionRules{}), nil)
+
+		assert.False(t, world.Snakes[0].Alive)
+		assert.Equal(t, []Event{{Type: EventDied, PlayerID: "1", Cause: CauseSnake, KillerID: "2"}}, events)
+	})
+
+	t.Run("should let a snake chase a tail when the rules allow it", func(t *testing.T) {
+		world, events := Step(chase(CollisionRules{TailChasing: true}), nil)
+
+		assert.True(t, world.Snakes[0].Alive)
+		assert.Empty(t, events)
+	})
+
+	// teammates makes the first snake run into the body of the second, both
+	// on the same team.
+	teammates := func(collisions CollisionRules) World {
+		world := newTestWorld(1, "1", "2")
+		world.Collisions = collisions
+		world.Foods = nil
+		world.Snakes[0].Body = []BodyFragment{{X: 10, Y: 30}, {X: 9, Y: 30}, {X: 8, Y: 30}}
+		world.Snakes[0].Moving = MoveRight
+		world.Snakes[0].Team = "red"
+		world.Snakes[1].Body = []BodyFragment{{X: 11, Y: 29}, {X: 11, Y: 30}, {X: 11, Y: 31}}
+		world.Snakes[1].Moving = MoveUp
+		world.Snakes[1].Team = "red"
+
+		return world
+	}
+
+	t.Run("should let a snake through the body of a teammate", func(t *testing.T) {
+		world, events := Step(teammates(CollisionRules{}), nil)
+
+		assert.True(t, world.Snakes[0].Alive)
+		assert.Empty(t, events)
+	})
+
+	t.Run("should kill a snake hitting a teammate with friendly fire", func(t *testing.T) {
+		world, events := Step(teammates(CollisionRules{FriendlyFire: true}), nil)
+
+		assert.False(t, world.Snakes[0].Alive)
+		assert.Equal(t, []Event{{Type: EventDied, PlayerID: "1", Cause: CauseSnake, KillerID: "2"}}, events)
+	})
+
+	t.Run("should kill a snake hitting a rival on another team", func(t *testing.T) {
+		world := teammates(CollisionRules{})
+		world.Snakes[1].Team = "blue"
+
+		world, _ = Step(world, nil)
+
+		assert.False(t, world.Snakes[0].Alive)
+	})
+}
@@ -0,0 +1,88 @@
+package game
+
+import "golang.org/x/exp/slices"
+
+// MaxTeams is how many teams the players of a match may be split in.
+const MaxTeams = 4
+
+var (
+	teamIDs    = []string{"red", "blue", "green", "yellow"}
+	teamColors = []string{"#e53935", "#1e88e5", "#43a047", "#fdd835"}
+)
+
+// TeamIDs lists the teams of a match split in n teams.
+func TeamIDs(n int) []string {
+	return teamIDs[:max(min(n, MaxTeams), 0)]
+}
+
+// TeamColor is the color the snakes of team are drawn with, empty for an
+// unknown team.
+func TeamColor(team string) string {
+	if i := slices.Index(teamIDs, team); i >= 0 {
+		return teamColors[i]
+	}
+
+	return ""
+}
+
+// balanceTeams spreads players over teams in turn, in the order they are
+// given.
+func balanceTeams(players []Player, teams []string) map[Player]string {
+	assigned := make(map[Player]string, len(players))
+
+	for i, player := range players {
+		assigned[player] = teams[i%len(teams)]
+	}
+
+	return assigned
+}
+
+// smallestTeam is the first of teams with the fewest players.
+func smallestTeam(players []Player, teams []string) string {
+	sizes := make(map[string]int, len(teams))
+	for _, player := range players {
+		sizes[player.GetTeam()]++
+	}
+
+	smallest := teams[0]
+	for _, team := range teams[1:] {
+		if sizes[team] < sizes[smallest] {
+			smallest = team
+		}
+	}
+
+	return smallest
+}
+
+// LastTeamStanding tells whether the snakes play in teams and at most one
+// team has snakes alive.
+func (w World) LastTeamStanding() bool {
+	teams := make(map[string]bool)
+	alive := make(map[string]bool)
+
+	for _, snake := range w.Snakes {
+		if snake.Team == "" {
+			continue
+		}
+
+		teams[snake.Team] = true
+
+		if snake.Alive {
+			alive[snake.Team] = true
+		}
+	}
+
+	return len(teams) > 1 && len(alive) <= 1
+}
+
+// teammates tells whether the i-th and j-th snakes are on the same team and
+// the collision rules let them through each other.
+func (w World) teammates(i, j int) bool {
+	if i == j || w.Collisions.FriendlyFire {
+		return false
+	}
+
+	team := w.Snakes[i].Team
+
+	return team != "" && team == w.Snakes[j].Team
+}
@@ -0,0 +1,204 @@
+package game
+
+import (
+	"golang.org/x/exp/slices"
+)
+
+const defaultInitialLength = 3
+
+// World is the whole simulated state of a running match. It is a plain
+// value: Step never changes the World it receives, so any World can be kept
+// around and stepped again with the same inputs to get the same result.
+type World struct {
+	Tick       uint64
+	Map        Map
+	Collisions CollisionRules
+	Shrink     ShrinkRules
+	ItemRules  ItemRules
+	Rand       Rand
+	Snakes     []Snake
+	Foods      []foodPosition
+	Items      []Item
+	nextItem   uint64
+}
+
+type Snake struct {
+	PlayerID   string
+	Body       []BodyFragment
+	Moving     movement
+	Movements  []movement
+	ToIncrease uint
+	LastTail   BodyFragment
+	Alive      bool
+	Control    control
+	Team       string
+	Effects    []Effect
+}
+
+// control tells who is steering a snake. A snake whose player lost the
+// connection is either frozen or steered by the autopilot until the player
+// comes back or the reconnect grace expires.
+type control int
+
+const (
+	ControlPlayer control = iota
+	ControlFrozen
+	ControlAutopilot
+	ControlLeft
+)
+
+// Input is either a move or, when Control is set, a change of who is
+// steering the snake.
+type Input struct {
+	PlayerID string
+	Move     movement
+	Control  *control
+}
+
+// Rules are the settings of a round that the simulation depends on. They are
+// kept in replays, so that the round can be simulated again.
+type Rules struct {
+	Map           Map               `json:"map"`
+	FoodsLimit    int               `json:"foods_limit"`
+	InitialLength int               `json:"initial_length"`
+	Collisions    CollisionRules    `json:"collisions"`
+	Teams         map[string]string `json:"teams,omitempty"`
+	Shrink        ShrinkRules       `json:"shrink,omitempty"`
+	Items         ItemRules         `json:"items,omitempty"`
+}
+
+// NewWorld places one snake per player, in the given order, at the spawns
+// picked by AllocateSpawns. Each snake is on the team the rules give its
+// player. The foods are summoned using a generator seeded with seed. The
+// map is meant to have been checked with ValidateMap; a snake that does not
+// fit on it starts the round dead.
+func NewWorld(seed int64, rules Rules, playerIDs []string) World {
+	world := World{
+		Map:        rules.Map,
+		Collisions: rules.Collisions,
+		Shrink:     rules.Shrink,
+		ItemRules:  rules.Items,
+		Rand:       NewRand(seed),
+		Snakes:     make([]Snake, 0, len(playerIDs)),
+		Foods:      make([]foodPosition, 0, rules.FoodsLimit),
+	}
+
+	length := rules.InitialLength
+	if length < 1 {
+		length = defaultInitialLength
+	}
+
+	spawns, _ := AllocateSpawns(rules.Map, len(playerIDs), length)
+
+	for i, playerID := range playerIDs {
+		snake := Snake{PlayerID: playerID, Team: rules.Teams[playerID]}
+
+		if i < len(spawns) {
+			snake.Body = spawns[i].Body
+			snake.Moving = spawns[i].Moving
+			snake.Alive = true
+		}
+
+		world.Snakes = append(world.Snakes, snake)
+	}
+
+	for i := 0; i < rules.FoodsLimit; i++ {
+		if position, ok := world.freePosition(); ok {
+			world.Foods = append(world.Foods, position)
+		}
+	}
+
+	return world
+}
+
+func (w World) clone() World {
+	snakes := make([]Snake, len(w.Snakes))
+
+	for i, snake := range w.Snakes {
+		snake.Body = slices.Clone(snake.Body)
+		snake.Movements = slices.Clone(snake.Movements)
+		snake.Effects = slices.Clone(snake.Effects)
+		snakes[i] = snake
+	}
+
+	w.Snakes = snakes
+	w.Foods = slices.Clone(w.Foods)
+	w.Items = slices.Clone(w.Items)
+
+	return w
+}
+
+func (w World) GetSnake(playerID string) (Snake, bool) {
+	for _, snake := range w.Snakes {
+		if snake.PlayerID == playerID {
+			return snake, true
+		}
+	}
+
+	return Snake{}, false
+}
+
+func (w World) HasAliveSnakes() bool {
+	for _, snake := range w.Snakes {
+		if snake.Alive {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AliveSnakes counts the snakes still alive.
+func (w World) AliveSnakes() int {
+	alive := 0
+
+	for _, snake := range w.Snakes {
+		if snake.Alive {
+			alive++
+		}
+	}
+
+	return alive
+}
+
+func (w World) occupiedTiles() map[BodyFragment]bool {
+	occupied := w.Map.wallSet()
+
+	for _, snake := range w.Snakes {
+		for _, bodyFragment := range snake.Body {
+			occupied[bodyFragment] = true
+		}
+	}
+
+	for _, food := range w.Foods {
+		occupied[BodyFragment{X: food.X, Y: food.Y}] = true
+	}
+
+	for _, item := range w.Items {
+		occupied[BodyFragment(item.Position)] = true
+	}
+
+	return occupied
+}
+
+// freePosition draws one of the tiles not covered by a wall, a snake, a food
+// or an item.
+func (w *World) freePosition() (foodPosition, bool) {
+	tiles := w.Map.Tiles
+	occupied := w.occupiedTiles()
+	free := make([]foodPosition, 0, tiles.Horizontal*tiles.Vertical)
+
+	for y := 0; y < tiles.Vertical; y++ {
+		for x := 0; x < tiles.Horizontal; x++ {
+			if !occupied[BodyFragment{X: x, Y: y}] {
+				free = append(free, foodPosition{X: x, Y: y})
+			}
+		}
+	}
+
+	if len(free) == 0 {
+		return foodPosition{}, false
+	}
+
+	return free[w.Rand.Intn(len(free))], true
+}
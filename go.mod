@@ -1,6 +1,6 @@
 module github.com/Maycon-Santos/go-snake-backend
 
-go 1.18
+go 1.21
 
 require (
 	github.com/go-redis/redis/v8 v8.11.5
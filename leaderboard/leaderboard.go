@@ -0,0 +1,229 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Maycon-Santos/go-snake-backend/cache"
+	"github.com/Maycon-Santos/go-snake-backend/db"
+)
+
+// Board is what a leaderboard ranks the accounts by.
+type Board string
+
+const (
+	BoardWins   = Board("wins")
+	BoardLength = Board("length")
+	BoardKills  = Board("kills")
+)
+
+// Boards lists every leaderboard.
+var Boards = []Board{BoardWins, BoardLength, BoardKills}
+
+// AllTime is the season of the leaderboards that are never reset.
+const AllTime = "all"
+
+var ErrUnknownBoard = errors.New("leaderboard: unknown board")
+
+// Season is the season a round that ended at t counts for. Seasons last a
+// calendar month in UTC and are named as 2006-01.
+func Season(t time.Time) string {
+	return t.UTC().Format("2006-01")
+}
+
+// ParseBoard checks that name is one of Boards.
+func ParseBoard(name string) (Board, error) {
+	for _, b := range Boards {
+		if string(b) == name {
+			return b, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrUnknownBoard, name)
+}
+
+// Entry is the place of an account on a leaderboard. Rank starts at 1.
+type Entry struct {
+	AccountID string
+	Rank      int64
+	Score     float64
+}
+
+// Leaderboards ranks the accounts by their wins, their longest snake and
+// their kills, for each season and all-time, in Redis sorted sets.
+type Leaderboards interface {
+	Record(ctx context.Context, match db.Match) error
+	Top(ctx context.Context, b Board, season string, limit int, offset int) ([]Entry, error)
+	Around(ctx context.Context, b Board, season string, accountID string, n int) ([]Entry, error)
+	Rebuild(ctx context.Context, stats []db.MonthlyStats) error
+}
+
+type leaderboards struct {
+	cacheClient cache.Client
+}
+
+func NewLeaderboards(cacheClient cache.Client) Leaderboards {
+	return leaderboards{cacheClient}
+}
+
+func key(b Board, season string) string {
+	return fmt.Sprintf("leaderboard:%s:%s", b, season)
+}
+
+// Record counts a round that was played to the end on the leaderboards of
+// its season and on the all-time ones, in a single transaction so a round
+// is never counted on some of them only. Every player gets on them, even
+// with nothing to add, so they can find themselves there.
+func (l leaderboards) Record(ctx context.Context, match db.Match) error {
+	return l.cacheClient.TxPipelined(ctx, func(tx cache.Tx) {
+		for _, season := range []string{Season(match.EndedAt), AllTime} {
+			for _, player := range match.Players {
+				wins := 0
+				if player.Placement == 1 {
+					wins = 1
+				}
+
+				tx.ZIncrBy(key(BoardWins, season), float64(wins), player.AccountID)
+				tx.ZAddGT(key(BoardLength, season), cache.Member{
+					Name:  player.AccountID,
+					Score: float64(player.Length),
+				})
+				tx.ZIncrBy(key(BoardKills, season), float64(player.Kills), player.AccountID)
+			}
+		}
+	})
+}
+
+// Top lists the entries of a leaderboard from the highest score.
+func (l leaderboards) Top(ctx context.Context, b Board, season string, limit int, offset int) ([]Entry, error) {
+	if limit <= 0 {
+		return make([]Entry, 0), nil
+	}
+
+	return l.list(ctx, b, season, int64(offset), int64(offset+limit-1))
+}
+
+// Around lists the account along with up to n entries above and below it.
+// It returns no entries when the account is not on the leaderboard.
+func (l leaderboards) Around(ctx context.Context, b Board, season string, accountID string, n int) ([]Entry, error) {
+	rank, ok, err := l.cacheClient.ZRevRank(ctx, key(b, season), accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return make([]Entry, 0), nil
+	}
+
+	return l.list(ctx, b, season, max(rank-int64(n), 0), rank+int64(n))
+}
+
+func (l leaderboards) list(ctx context.Context, b Board, season string, start int64, stop int64) ([]Entry, error) {
+	members, err := l.cacheClient.ZRevRange(ctx, key(b, season), start, stop)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(members))
+
+	for i, member := range members {
+		entries = append(entries, Entry{
+			AccountID: member.Name,
+			Rank:      start + int64(i) + 1,
+			Score:     member.Score,
+		})
+	}
+
+	return entries, nil
+}
+
+// Rebuild recomputes the leaderboards from the monthly stats of the match
+// history. Each leaderboard is written aside and then put in place of the
+// one in use, so they are never seen half built. The leaderboards of the
+// seasons the stats no longer have are deleted.
+func (l leaderboards) Rebuild(ctx context.Context, stats []db.MonthlyStats) error {
+	existing, err := l.cacheClient.Keys(ctx, key("*", "*"))
+	if err != nil {
+		return err
+	}
+
+	members := make(map[string][]cache.Member)
+	allTime := make(map[string]db.MonthlyStats)
+	accounts := make([]string, 0)
+
+	for _, monthly := range stats {
+		season := Season(monthly.Month)
+
+		members[key(BoardWins, season)] = append(members[key(BoardWins, season)], cache.Member{
+			Name:  monthly.AccountID,
+			Score: float64(monthly.Wins),
+		})
+		members[key(BoardLength, season)] = append(members[key(BoardLength, season)], cache.Member{
+			Name:  monthly.AccountID,
+			Score: float64(monthly.BestLength),
+		})
+		members[key(BoardKills, season)] = append(members[key(BoardKills, season)], cache.Member{
+			Name:  monthly.AccountID,
+			Score: float64(monthly.Kills),
+		})
+
+		total, ok := allTime[monthly.AccountID]
+		if !ok {
+			accounts = append(accounts, monthly.AccountID)
+		}
+
+		total.Wins += monthly.Wins
+		total.BestLength = max(total.BestLength, monthly.BestLength)
+		total.Kills += monthly.Kills
+		allTime[monthly.AccountID] = total
+	}
+
+	for _, accountID := range accounts {
+		total := allTime[accountID]
+
+		members[key(BoardWins, AllTime)] = append(members[key(BoardWins, AllTime)], cache.Member{
+			Name:  accountID,
+			Score: float64(total.Wins),
+		})
+		members[key(BoardLength, AllTime)] = append(members[key(BoardLength, AllTime)], cache.Member{
+			Name:  accountID,
+			Score: float64(total.BestLength),
+		})
+		members[key(BoardKills, AllTime)] = append(members[key(BoardKills, AllTime)], cache.Member{
+			Name:  accountID,
+			Score: float64(total.Kills),
+		})
+	}
+
+	for k, m := range members {
+		rebuilt := k + ":rebuild"
+
+		if err := l.cacheClient.Del(ctx, rebuilt); err != nil {
+			return err
+		}
+
+		if err := l.cacheClient.ZAdd(ctx, rebuilt, m...); err != nil {
+			return err
+		}
+
+		if err := l.cacheClient.Rename(ctx, rebuilt, k); err != nil {
+			return err
+		}
+	}
+
+	stale := make([]string, 0)
+
+	for _, k := range existing {
+		if _, ok := members[k]; !ok {
+			stale = append(stale, k)
+		}
+	}
+
+	if len(stale) == 0 {
+		return nil
+	}
+
+	return l.cacheClient.Del(ctx, stale...)
+}
@@ -0,0 +1,121 @@
+package leaderboard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Maycon-Santos/go-snake-backend/cache"
+	"github.com/Maycon-Santos/go-snake-backend/db"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeaderboards(t *testing.T) {
+	ctx := context.Background()
+	endedAt := time.Date(2023, 11, 12, 19, 0, 0, 0, time.UTC)
+
+	t.Run("should count a round on its season and all-time", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cacheClient := cache.NewMockClient(ctrl)
+		tx := cache.NewMockTx(ctrl)
+		leaderboards := NewLeaderboards(cacheClient)
+
+		cacheClient.EXPECT().TxPipelined(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, fn func(tx cache.Tx)) error {
+				fn(tx)
+				return nil
+			},
+		)
+
+		for _, season := range []string{"2023-11", AllTime} {
+			tx.EXPECT().ZIncrBy("leaderboard:wins:"+season, float64(1), "1")
+			tx.EXPECT().ZIncrBy("leaderboard:wins:"+season, float64(0), "2")
+			tx.EXPECT().ZAddGT("leaderboard:length:"+season, cache.Member{Name: "1", Score: 9})
+			tx.EXPECT().ZAddGT("leaderboard:length:"+season, cache.Member{Name: "2", Score: 4})
+			tx.EXPECT().ZIncrBy("leaderboard:kills:"+season, float64(1), "1")
+			tx.EXPECT().ZIncrBy("leaderboard:kills:"+season, float64(0), "2")
+		}
+
+		err := leaderboards.Record(ctx, db.Match{
+			EndedAt: endedAt,
+			Players: []db.MatchPlayer{
+				{AccountID: "1", Placement: 1, Length: 9, Kills: 1},
+				{AccountID: "2", Placement: 2, Length: 4},
+			},
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("should rank the entries around an account", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cacheClient := cache.NewMockClient(ctrl)
+		leaderboards := NewLeaderboards(cacheClient)
+
+		cacheClient.EXPECT().ZRevRank(ctx, "leaderboard:kills:all", "3").Return(int64(1), true, nil)
+		cacheClient.EXPECT().ZRevRange(ctx, "leaderboard:kills:all", int64(0), int64(3)).Return([]cache.Member{
+			{Name: "1", Score: 8},
+			{Name: "3", Score: 5},
+			{Name: "2", Score: 2},
+		}, nil)
+
+		entries, err := leaderboards.Around(ctx, BoardKills, AllTime, "3", 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Entry{
+			{AccountID: "1", Rank: 1, Score: 8},
+			{AccountID: "3", Rank: 2, Score: 5},
+			{AccountID: "2", Rank: 3, Score: 2},
+		}, entries)
+	})
+
+	t.Run("should list no entries around an account not on the leaderboard", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cacheClient := cache.NewMockClient(ctrl)
+		leaderboards := NewLeaderboards(cacheClient)
+
+		cacheClient.EXPECT().ZRevRank(ctx, "leaderboard:wins:2023-11", "3").Return(int64(0), false, nil)
+
+		entries, err := leaderboards.Around(ctx, BoardWins, "2023-11", "3", 2)
+
+		assert.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("should rebuild the seasons, sum them up all-time and drop the stale ones", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cacheClient := cache.NewMockClient(ctrl)
+		leaderboards := NewLeaderboards(cacheClient)
+
+		rebuilt := make(map[string][]cache.Member)
+
+		cacheClient.EXPECT().Keys(ctx, "leaderboard:*:*").Return([]string{
+			"leaderboard:wins:2023-09",
+			"leaderboard:wins:2023-11",
+			"leaderboard:kills:all",
+		}, nil)
+		cacheClient.EXPECT().Del(ctx, "leaderboard:wins:2023-09").Return(nil)
+		cacheClient.EXPECT().Del(ctx, gomock.Any()).Return(nil).Times(9)
+		cacheClient.EXPECT().Rename(ctx, gomock.Any(), gomock.Any()).Return(nil).Times(9)
+		cacheClient.EXPECT().ZAdd(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, key string, members ...cache.Member) error {
+				rebuilt[key] = members
+				return nil
+			},
+		).Times(9)
+
+		err := leaderboards.Rebuild(ctx, []db.MonthlyStats{
+			{AccountID: "1", Month: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), Wins: 2, BestLength: 12, Kills: 3},
+			{AccountID: "1", Month: time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC), Wins: 1, BestLength: 8, Kills: 4},
+			{AccountID: "2", Month: time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC), Wins: 5, BestLength: 9, Kills: 0},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []cache.Member{{Name: "1", Score: 2}}, rebuilt["leaderboard:wins:2023-10:rebuild"])
+		assert.Equal(t, []cache.Member{{Name: "1", Score: 1}, {Name: "2", Score: 5}}, rebuilt["leaderboard:wins:2023-11:rebuild"])
+		assert.Equal(t, []cache.Member{{Name: "1", Score: 3}, {Name: "2", Score: 5}}, rebuilt["leaderboard:wins:all:rebuild"])
+		assert.Equal(t, []cache.Member{{Name: "1", Score: 12}, {Name: "2", Score: 9}}, rebuilt["leaderboard:length:all:rebuild"])
+		assert.Equal(t, []cache.Member{{Name: "1", Score: 7}, {Name: "2", Score: 0}}, rebuilt["leaderboard:kills:all:rebuild"])
+	})
+}
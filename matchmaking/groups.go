@@ -0,0 +1,118 @@
+package matchmaking
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// formGroups groups the tickets that can play together at now, returning
+// the groups, the tickets still waiting and the ones that timed out.
+//
+// The tickets that waited the longest are served first: each of them, in
+// turn, is grouped with the tickets closest to its rating that every
+// member of the group accepts, and the group is formed only once it is full.
+// The tickets keep the order they were queued in.
+func formGroups(config Config, tickets []Ticket, now time.Time) ([]Group, []Ticket, []Ticket) {
+	groups := make([]Group, 0)
+	waiting := make([]Ticket, 0, len(tickets))
+	expired := make([]Ticket, 0)
+
+	queued := make([]Ticket, 0, len(tickets))
+
+	for _, ticket := range tickets {
+		if now.Sub(ticket.EnqueuedAt) >= config.Timeout {
+			expired = append(expired, ticket)
+		} else {
+			queued = append(queued, ticket)
+		}
+	}
+
+	sort.SliceStable(queued, func(a, b int) bool {
+		return queued[a].EnqueuedAt.Before(queued[b].EnqueuedAt)
+	})
+
+	grouped := make(map[string]bool)
+
+	for _, mode := range config.Modes {
+		for i, anchor := range queued {
+			if anchor.Mode != mode.Name || grouped[anchor.AccountID] {
+				continue
+			}
+
+			candidates := make([]Ticket, 0)
+
+			for _, ticket := range queued[i+1:] {
+				if ticket.Mode == mode.Name && !grouped[ticket.AccountID] && compatible(config, anchor, ticket, now) {
+					candidates = append(candidates, ticket)
+				}
+			}
+
+			sort.SliceStable(candidates, func(a, b int) bool {
+				return math.Abs(candidates[a].Rating-anchor.Rating) < math.Abs(candidates[b].Rating-anchor.Rating)
+			})
+
+			members := []Ticket{anchor}
+
+			for _, candidate := range candidates {
+				if len(members) == mode.Players {
+					break
+				}
+
+				fits := true
+
+				for _, member := range members[1:] {
+					if !compatible(config, member, candidate, now) {
+						fits = false
+						break
+					}
+				}
+
+				if fits {
+					members = append(members, candidate)
+				}
+			}
+
+			if len(members) < mode.Players {
+				continue
+			}
+
+			for _, member := range members {
+				grouped[member.AccountID] = true
+			}
+
+			groups = append(groups, Group{Mode: mode, Tickets: members})
+		}
+	}
+
+	for _, ticket := range tickets {
+		if !grouped[ticket.AccountID] && now.Sub(ticket.EnqueuedAt) < config.Timeout {
+			waiting = append(waiting, ticket)
+		}
+	}
+
+	return groups, waiting, expired
+}
+
+// compatible tells whether the tickets a and b accept each other: their
+// ratings are within the band of both and they are from the same region,
+// unless both waited long enough to play with any region.
+func compatible(config Config, a, b Ticket, now time.Time) bool {
+	difference := math.Abs(a.Rating - b.Rating)
+	if difference > band(config, a, now) || difference > band(config, b, now) {
+		return false
+	}
+
+	if a.Region == "" || b.Region == "" || a.Region == b.Region {
+		return true
+	}
+
+	return now.Sub(a.EnqueuedAt) >= config.AnyRegionAfter && now.Sub(b.EnqueuedAt) >= config.AnyRegionAfter
+}
+
+// band is how far from its rating a ticket accepts players at now.
+func band(config Config, ticket Ticket, now time.Time) float64 {
+	waited := now.Sub(ticket.EnqueuedAt).Seconds()
+
+	return math.Min(config.RatingBand+config.BandGrowth*waited, config.MaxRatingBand)
+}
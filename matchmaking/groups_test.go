@@ -0,0 +1,119 @@
+package matchmaking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testConfig = Config{
+	Modes:          []Mode{{Name: "duel", Players: 2}, {Name: "ffa", Players: 3}},
+	RatingBand:     100,
+	BandGrowth:     10,
+	MaxRatingBand:  400,
+	AnyRegionAfter: 30 * time.Second,
+	Timeout:        time.Minute,
+}
+
+func accountIDs(tickets []Ticket) []string {
+	ids := make([]string, 0, len(tickets))
+
+	for _, ticket := range tickets {
+		ids = append(ids, ticket.AccountID)
+	}
+
+	return ids
+}
+
+func TestFormGroups(t *testing.T) {
+	start := time.Date(2023, 11, 20, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should group the closest ratings of a mode", func(t *testing.T) {
+		tickets := []Ticket{
+			{AccountID: "1", Rating: 1500, Mode: "duel", EnqueuedAt: start},
+			{AccountID: "2", Rating: 1590, Mode: "duel", EnqueuedAt: start},
+			{AccountID: "3", Rating: 1520, Mode: "duel", EnqueuedAt: start},
+			{AccountID: "4", Rating: 1510, Mode: "ffa", EnqueuedAt: start},
+		}
+
+		groups, waiting, expired := formGroups(testConfig, tickets, start)
+
+		assert.Len(t, groups, 1)
+		assert.Equal(t, []string{"1", "3"}, accountIDs(groups[0].Tickets))
+		assert.Equal(t, "duel", groups[0].Mode.Name)
+		assert.Equal(t, []string{"2", "4"}, accountIDs(waiting))
+		assert.Empty(t, expired)
+	})
+
+	t.Run("should widen the rating band as the tickets wait", func(t *testing.T) {
+		tickets := []Ticket{
+			{AccountID: "1", Rating: 1500, Mode: "duel", EnqueuedAt: start},
+			{AccountID: "2", Rating: 1750, Mode: "duel", EnqueuedAt: start},
+		}
+
+		groups, _, _ := formGroups(testConfig, tickets, start.Add(10*time.Second))
+		assert.Empty(t, groups)
+
+		groups, _, _ = formGroups(testConfig, tickets, start.Add(15*time.Second))
+		assert.Len(t, groups, 1)
+	})
+
+	t.Run("should group regions together only after waiting", func(t *testing.T) {
+		tickets := []Ticket{
+			{AccountID: "1", Rating: 1500, Region: "sa", Mode: "duel", EnqueuedAt: start},
+			{AccountID: "2", Rating: 1500, Region: "eu", Mode: "duel", EnqueuedAt: start.Add(5 * time.Second)},
+			{AccountID: "3", Rating: 1500, Mode: "duel", EnqueuedAt: start.Add(10 * time.Second)},
+		}
+
+		groups, waiting, _ := formGroups(testConfig, tickets, start.Add(10*time.Second))
+		assert.Len(t, groups, 1)
+		assert.Equal(t, []string{"1", "3"}, accountIDs(groups[0].Tickets))
+		assert.Equal(t, []string{"2"}, accountIDs(waiting))
+
+		tickets = []Ticket{
+			{AccountID: "1", Rating: 1500, Region: "sa", Mode: "duel", EnqueuedAt: start},
+			{AccountID: "2", Rating: 1500, Region: "eu", Mode: "duel", EnqueuedAt: start.Add(5 * time.Second)},
+		}
+
+		groups, _, _ = formGroups(testConfig, tickets, start.Add(30*time.Second))
+		assert.Empty(t, groups)
+
+		groups, _, _ = formGroups(testConfig, tickets, start.Add(35*time.Second))
+		assert.Len(t, groups, 1)
+	})
+
+	t.Run("should form a group only once it is full", func(t *testing.T) {
+		tickets := []Ticket{
+			{AccountID: "1", Rating: 1500, Mode: "ffa", EnqueuedAt: start},
+			{AccountID: "2", Rating: 1500, Mode: "ffa", EnqueuedAt: start},
+		}
+
+		groups, waiting, _ := formGroups(testConfig, tickets, start)
+		assert.Empty(t, groups)
+		assert.Len(t, waiting, 2)
+	})
+
+	t.Run("should keep every member within the band of the others", func(t *testing.T) {
+		tickets := []Ticket{
+			{AccountID: "1", Rating: 1500, Mode: "ffa", EnqueuedAt: start},
+			{AccountID: "2", Rating: 1420, Mode: "ffa", EnqueuedAt: start},
+			{AccountID: "3", Rating: 1580, Mode: "ffa", EnqueuedAt: start},
+		}
+
+		groups, _, _ := formGroups(testConfig, tickets, start)
+		assert.Empty(t, groups)
+	})
+
+	t.Run("should time out the tickets that waited too long", func(t *testing.T) {
+		tickets := []Ticket{
+			{AccountID: "1", Rating: 1500, Mode: "ffa", EnqueuedAt: start},
+			{AccountID: "2", Rating: 1500, Mode: "ffa", EnqueuedAt: start.Add(30 * time.Second)},
+		}
+
+		groups, waiting, expired := formGroups(testConfig, tickets, start.Add(time.Minute))
+		assert.Empty(t, groups)
+		assert.Equal(t, []string{"2"}, accountIDs(waiting))
+		assert.Equal(t, []string{"1"}, accountIDs(expired))
+	})
+}
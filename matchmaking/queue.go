@@ -0,0 +1,340 @@
+package matchmaking
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrAlreadyQueued = errors.New("matchmaking: the account is already queued")
+	ErrUnknownMode   = errors.New("matchmaking: unknown mode")
+)
+
+type status string
+
+const (
+	StatusQueued   = status("QUEUED")
+	StatusMatched  = status("MATCHED")
+	StatusTimedOut = status("TIMED_OUT")
+	StatusCanceled = status("CANCELED")
+	StatusFailed   = status("FAILED")
+)
+
+// Mode is a way of playing players queue for. A group of a mode is formed
+// once Players players can play together.
+type Mode struct {
+	Name    string
+	Players int
+	Teams   int
+}
+
+// DefaultModes are the modes players can queue for. The players of a mode
+// with teams are split in that many teams once in the match.
+var DefaultModes = []Mode{
+	{Name: "duel", Players: 2},
+	{Name: "ffa", Players: 4},
+	{Name: "teams", Players: 4, Teams: 2},
+}
+
+// Config tunes how the players are grouped.
+//
+// A ticket first matches players whose rating is within RatingBand of its
+// own. The band grows by BandGrowth every second the ticket waits, up to
+// MaxRatingBand. After AnyRegionAfter, it matches players of any region
+// that waited as long. A ticket that waited Timeout leaves the queue.
+type Config struct {
+	Modes          []Mode
+	RatingBand     float64
+	BandGrowth     float64
+	MaxRatingBand  float64
+	AnyRegionAfter time.Duration
+	Timeout        time.Duration
+	Interval       time.Duration
+}
+
+// Ticket is a player waiting in the queue. An empty Region matches every
+// region.
+type Ticket struct {
+	AccountID  string
+	Rating     float64
+	Region     string
+	Mode       string
+	EnqueuedAt time.Time
+}
+
+// Status is where a ticket stands. MatchID is set once it is matched.
+type Status struct {
+	Status  status
+	Ticket  Ticket
+	Band    float64
+	MatchID string
+}
+
+// Group is a set of tickets that play a match together.
+type Group struct {
+	Mode    Mode
+	Tickets []Ticket
+}
+
+type Queue interface {
+	Enqueue(ticket Ticket) error
+	Dequeue(accountID string) bool
+	GetStatus(accountID string) (Status, bool)
+	Subscribe(accountID string) (<-chan Status, func())
+	OnGroup(fn func(group Group) (string, error))
+	Close()
+}
+
+type queue struct {
+	config  Config
+	now     func() time.Time
+	tickets []Ticket
+	// results keeps how the tickets that left the queue ended up, so a
+	// player who subscribes late still learns about it. They are dropped
+	// once the ticket is twice as old as the timeout.
+	results     map[string]Status
+	subscribers map[string][]chan Status
+	onGroup     func(group Group) (string, error)
+	done        chan struct{}
+
+	sync      sync.Mutex
+	closeOnce sync.Once
+}
+
+func NewQueue(config Config) Queue {
+	q := newQueue(config, time.Now)
+
+	go q.run()
+
+	return q
+}
+
+func newQueue(config Config, now func() time.Time) *queue {
+	return &queue{
+		config:      config,
+		now:         now,
+		tickets:     make([]Ticket, 0),
+		results:     make(map[string]Status),
+		subscribers: make(map[string][]chan Status),
+		done:        make(chan struct{}),
+	}
+}
+
+func (q *queue) mode(name string) (Mode, bool) {
+	for _, mode := range q.config.Modes {
+		if mode.Name == name {
+			return mode, true
+		}
+	}
+
+	return Mode{}, false
+}
+
+// Enqueue puts the ticket in the queue. EnqueuedAt is set to now.
+func (q *queue) Enqueue(ticket Ticket) error {
+	if _, ok := q.mode(ticket.Mode); !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownMode, ticket.Mode)
+	}
+
+	q.sync.Lock()
+	defer q.sync.Unlock()
+
+	for _, queued := range q.tickets {
+		if queued.AccountID == ticket.AccountID {
+			return ErrAlreadyQueued
+		}
+	}
+
+	ticket.EnqueuedAt = q.now()
+	q.tickets = append(q.tickets, ticket)
+	delete(q.results, ticket.AccountID)
+
+	q.notify(Status{
+		Status: StatusQueued,
+		Ticket: ticket,
+		Band:   q.band(ticket, ticket.EnqueuedAt),
+	})
+
+	return nil
+}
+
+// Dequeue takes the account out of the queue. It reports false when the
+// account was not queued.
+func (q *queue) Dequeue(accountID string) bool {
+	q.sync.Lock()
+	defer q.sync.Unlock()
+
+	for i, ticket := range q.tickets {
+		if ticket.AccountID == accountID {
+			q.tickets = append(q.tickets[:i], q.tickets[i+1:]...)
+			q.finish(Status{Status: StatusCanceled, Ticket: ticket})
+			return true
+		}
+	}
+
+	return false
+}
+
+func (q *queue) GetStatus(accountID string) (Status, bool) {
+	q.sync.Lock()
+	defer q.sync.Unlock()
+
+	return q.status(accountID)
+}
+
+func (q *queue) status(accountID string) (Status, bool) {
+	for _, ticket := range q.tickets {
+		if ticket.AccountID == accountID {
+			return Status{
+				Status: StatusQueued,
+				Ticket: ticket,
+				Band:   q.band(ticket, q.now()),
+			}, true
+		}
+	}
+
+	result, ok := q.results[accountID]
+
+	return result, ok
+}
+
+// Subscribe receives the status of the account as it changes, starting with
+// the current one. Only the latest status is kept for a subscriber that
+// falls behind. The returned function stops the subscription.
+func (q *queue) Subscribe(accountID string) (<-chan Status, func()) {
+	ch := make(chan Status, 1)
+
+	q.sync.Lock()
+	q.subscribers[accountID] = append(q.subscribers[accountID], ch)
+
+	if current, ok := q.status(accountID); ok {
+		ch <- current
+	}
+	q.sync.Unlock()
+
+	unsubscribe := func() {
+		q.sync.Lock()
+		defer q.sync.Unlock()
+
+		subscribers := q.subscribers[accountID]
+		for i, subscriber := range subscribers {
+			if subscriber == ch {
+				q.subscribers[accountID] = append(subscribers[:i], subscribers[i+1:]...)
+				break
+			}
+		}
+
+		if len(q.subscribers[accountID]) == 0 {
+			delete(q.subscribers, accountID)
+		}
+	}
+
+	return ch, unsubscribe
+}
+
+// OnGroup sets how the match of a group is created. fn returns the ID of
+// the match, which is sent to every member of the group. When fn fails the
+// members are only told the match failed, so fn is expected to report the
+// error itself.
+func (q *queue) OnGroup(fn func(group Group) (string, error)) {
+	q.sync.Lock()
+	q.onGroup = fn
+	q.sync.Unlock()
+}
+
+func (q *queue) Close() {
+	q.closeOnce.Do(func() {
+		close(q.done)
+	})
+}
+
+func (q *queue) run() {
+	ticker := time.NewTicker(q.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-q.done:
+			return
+		case <-ticker.C:
+			q.search()
+		}
+	}
+}
+
+// search forms the groups it can, creates their matches and tells every
+// ticket where it stands.
+func (q *queue) search() {
+	q.sync.Lock()
+
+	now := q.now()
+	groups, waiting, expired := formGroups(q.config, q.tickets, now)
+	q.tickets = waiting
+	onGroup := q.onGroup
+
+	for _, ticket := range expired {
+		q.finish(Status{Status: StatusTimedOut, Ticket: ticket})
+	}
+
+	for _, ticket := range waiting {
+		q.notify(Status{
+			Status: StatusQueued,
+			Ticket: ticket,
+			Band:   q.band(ticket, now),
+		})
+	}
+
+	for accountID, result := range q.results {
+		if now.Sub(result.Ticket.EnqueuedAt) > 2*q.config.Timeout {
+			delete(q.results, accountID)
+		}
+	}
+
+	q.sync.Unlock()
+
+	for _, group := range groups {
+		result := Status{Status: StatusFailed}
+
+		if onGroup != nil {
+			matchID, err := onGroup(group)
+			if err == nil {
+				result = Status{Status: StatusMatched, MatchID: matchID}
+			}
+		}
+
+		q.sync.Lock()
+		for _, ticket := range group.Tickets {
+			result.Ticket = ticket
+			q.finish(result)
+		}
+		q.sync.Unlock()
+	}
+}
+
+// finish records how a ticket that left the queue ended up and tells its
+// subscribers.
+func (q *queue) finish(result Status) {
+	q.results[result.Ticket.AccountID] = result
+	q.notify(result)
+}
+
+func (q *queue) notify(s Status) {
+	for _, ch := range q.subscribers[s.Ticket.AccountID] {
+		select {
+		case ch <- s:
+		default:
+			select {
+			case <-ch:
+			default:
+			}
+
+			ch <- s
+		}
+	}
+}
+
+func (q *queue) band(ticket Ticket, now time.Time) float64 {
+	return band(q.config, ticket, now)
+}
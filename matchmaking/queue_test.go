@@ -0,0 +1,110 @@
+package matchmaking
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueue(t *testing.T) {
+	now := time.Date(2023, 11, 20, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	t.Run("should tell the members of a group the match they play", func(t *testing.T) {
+		q := newQueue(testConfig, clock)
+
+		q.OnGroup(func(group Group) (string, error) {
+			return "match", nil
+		})
+
+		assert.NoError(t, q.Enqueue(Ticket{AccountID: "1", Rating: 1500, Mode: "duel"}))
+		assert.ErrorIs(t, q.Enqueue(Ticket{AccountID: "1", Rating: 1500, Mode: "duel"}), ErrAlreadyQueued)
+		assert.ErrorIs(t, q.Enqueue(Ticket{AccountID: "2", Rating: 1500, Mode: "solo"}), ErrUnknownMode)
+
+		statuses, unsubscribe := q.Subscribe("1")
+		defer unsubscribe()
+
+		assert.Equal(t, StatusQueued, (<-statuses).Status)
+
+		assert.NoError(t, q.Enqueue(Ticket{AccountID: "2", Rating: 1550, Mode: "duel"}))
+		q.search()
+
+		status := <-statuses
+		assert.Equal(t, StatusMatched, status.Status)
+		assert.Equal(t, "match", status.MatchID)
+
+		status, ok := q.GetStatus("2")
+		assert.True(t, ok)
+		assert.Equal(t, "match", status.MatchID)
+	})
+
+	t.Run("should cancel a ticket", func(t *testing.T) {
+		q := newQueue(testConfig, clock)
+
+		assert.NoError(t, q.Enqueue(Ticket{AccountID: "1", Rating: 1500, Mode: "duel"}))
+		assert.True(t, q.Dequeue("1"))
+		assert.False(t, q.Dequeue("1"))
+
+		status, _ := q.GetStatus("1")
+		assert.Equal(t, StatusCanceled, status.Status)
+
+		assert.NoError(t, q.Enqueue(Ticket{AccountID: "1", Rating: 1500, Mode: "duel"}))
+	})
+
+	t.Run("should time out a ticket that waited too long", func(t *testing.T) {
+		later := now
+		q := newQueue(testConfig, func() time.Time { return later })
+
+		assert.NoError(t, q.Enqueue(Ticket{AccountID: "1", Rating: 1500, Mode: "duel"}))
+
+		later = now.Add(testConfig.Timeout + time.Second)
+		q.search()
+
+		status, ok := q.GetStatus("1")
+		assert.True(t, ok)
+		assert.Equal(t, StatusTimedOut, status.Status)
+	})
+
+	t.Run("should tell a late subscriber how its ticket ended up", func(t *testing.T) {
+		q := newQueue(testConfig, clock)
+
+		q.OnGroup(func(group Group) (string, error) {
+			return "match", nil
+		})
+
+		assert.NoError(t, q.Enqueue(Ticket{AccountID: "1", Rating: 1500, Mode: "duel"}))
+		assert.NoError(t, q.Enqueue(Ticket{AccountID: "2", Rating: 1520, Mode: "duel"}))
+		q.search()
+
+		statuses, unsubscribe := q.Subscribe("2")
+		defer unsubscribe()
+
+		select {
+		case status := <-statuses:
+			assert.Equal(t, StatusMatched, status.Status)
+			assert.Equal(t, "match", status.MatchID)
+		default:
+			t.Fatal("the subscriber should have been told the match")
+		}
+	})
+
+	t.Run("should fail the tickets of a group whose match is not created", func(t *testing.T) {
+		q := newQueue(testConfig, clock)
+
+		q.OnGroup(func(group Group) (string, error) {
+			return "", errors.New("no match")
+		})
+
+		assert.NoError(t, q.Enqueue(Ticket{AccountID: "1", Rating: 1500, Mode: "duel"}))
+		assert.NoError(t, q.Enqueue(Ticket{AccountID: "2", Rating: 1520, Mode: "duel"}))
+		q.search()
+
+		for _, accountID := range []string{"1", "2"} {
+			status, _ := q.GetStatus(accountID)
+			assert.Equal(t, StatusFailed, status.Status)
+			assert.Empty(t, status.MatchID)
+		}
+	})
+}
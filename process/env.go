@@ -24,14 +24,32 @@ type JWT struct {
 	RefreshExpiresIn time.Duration `mapstructure:"jwt_refresh_expires_in"`
 }
 
+type Match struct {
+	ReconnectGrace time.Duration `mapstructure:"match_reconnect_grace"`
+	ReconnectMode  string        `mapstructure:"match_reconnect_mode"`
+}
+
+type Matchmaking struct {
+	RatingBand     float64       `mapstructure:"matchmaking_rating_band"`
+	BandGrowth     float64       `mapstructure:"matchmaking_band_growth"`
+	MaxRatingBand  float64       `mapstructure:"matchmaking_max_rating_band"`
+	AnyRegionAfter time.Duration `mapstructure:"matchmaking_any_region_after"`
+	Timeout        time.Duration `mapstructure:"matchmaking_timeout"`
+	JoinTimeout    time.Duration `mapstructure:"matchmaking_join_timeout"`
+	Interval       time.Duration `mapstructure:"matchmaking_interval"`
+}
+
 type Env struct {
-	AppName                   string   `mapstructure:"app_name"`
-	ServerPort                int      `mapstructure:"server_port"`
-	RedisAddress              string   `mapstructure:"redis_address"`
-	JWT                       JWT      `mapstructure:",squash"`
-	Database                  Database `mapstructure:",squash"`
-	AccessControlAllowOrigin  string   `mapstructure:"access_control_allow_origin"`
-	AccessControlAllowHeaders string   `mapstructure:"access_control_allow_headers"`
+	AppName                   string      `mapstructure:"app_name"`
+	ServerPort                int         `mapstructure:"server_port"`
+	MetricsAddress            string      `mapstructure:"metrics_address"`
+	RedisAddress              string      `mapstructure:"redis_address"`
+	JWT                       JWT         `mapstructure:",squash"`
+	Database                  Database    `mapstructure:",squash"`
+	Match                     Match       `mapstructure:",squash"`
+	Matchmaking               Matchmaking `mapstructure:",squash"`
+	AccessControlAllowOrigin  string      `mapstructure:"access_control_allow_origin"`
+	AccessControlAllowHeaders string      `mapstructure:"access_control_allow_headers"`
 }
 
 func NewEnv() (*Env, error) {
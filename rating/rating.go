@@ -0,0 +1,98 @@
+package rating
+
+import (
+	"context"
+	"math"
+
+	"github.com/Maycon-Santos/go-snake-backend/db"
+)
+
+const (
+	// Initial is the rating of an account that never played a rated round.
+	Initial = 1500.0
+
+	// kFactor is how much a single round can move a rating.
+	kFactor = 32.0
+)
+
+// Player is how an account did in a rated round.
+type Player struct {
+	Rating    float64
+	Placement int
+}
+
+// Update works out the new rating of each player of a free-for-all round.
+//
+// The round is rated as if every player had played everyone else one on
+// one with Elo, winning against the players placed after it and drawing
+// with the ones sharing its placement. The change of each pairing is scaled
+// down by the number of opponents, so a round moves a rating by at most
+// kFactor whatever the number of players, and the ratings always add up to
+// the same total as before.
+func Update(players []Player) []float64 {
+	ratings := make([]float64, len(players))
+
+	for i, player := range players {
+		ratings[i] = player.Rating
+	}
+
+	if len(players) < 2 {
+		return ratings
+	}
+
+	k := kFactor / float64(len(players)-1)
+
+	for i, player := range players {
+		for j, opponent := range players {
+			if i == j {
+				continue
+			}
+
+			expected := 1 / (1 + math.Pow(10, (opponent.Rating-player.Rating)/400))
+
+			score := 0.5
+			if player.Placement < opponent.Placement {
+				score = 1
+			} else if player.Placement > opponent.Placement {
+				score = 0
+			}
+
+			ratings[i] += k * (score - expected)
+		}
+	}
+
+	return ratings
+}
+
+// Rate updates the ratings of the accounts that played a round saved in the
+// match history as matchID. Rounds played alone are not rated.
+func Rate(ctx context.Context, ratingsRepository db.RatingsRepository, matchID string, match db.Match) error {
+	if len(match.Players) < 2 {
+		return nil
+	}
+
+	accountIDs := make([]string, 0, len(match.Players))
+	placements := make(map[string]int, len(match.Players))
+
+	for _, player := range match.Players {
+		accountIDs = append(accountIDs, player.AccountID)
+		placements[player.AccountID] = player.Placement
+	}
+
+	return ratingsRepository.Update(ctx, matchID, accountIDs, Initial, func(ratings []db.Rating) []db.Rating {
+		players := make([]Player, 0, len(ratings))
+
+		for _, rating := range ratings {
+			players = append(players, Player{
+				Rating:    rating.Rating,
+				Placement: placements[rating.AccountID],
+			})
+		}
+
+		for i, updated := range Update(players) {
+			ratings[i].Rating = updated
+		}
+
+		return ratings
+	})
+}
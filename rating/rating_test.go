@@ -0,0 +1,63 @@
+package rating
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdate(t *testing.T) {
+	t.Run("should move equal ratings by half the k factor in a duel", func(t *testing.T) {
+		ratings := Update([]Player{
+			{Rating: Initial, Placement: 1},
+			{Rating: Initial, Placement: 2},
+		})
+
+		assert.InDelta(t, Initial+kFactor/2, ratings[0], 1e-9)
+		assert.InDelta(t, Initial-kFactor/2, ratings[1], 1e-9)
+	})
+
+	t.Run("should keep the total of the ratings", func(t *testing.T) {
+		players := []Player{
+			{Rating: 1620, Placement: 3},
+			{Rating: 1480, Placement: 1},
+			{Rating: 1500, Placement: 2},
+			{Rating: 1390, Placement: 3},
+		}
+
+		ratings := Update(players)
+
+		total := 0.0
+		for i, rating := range ratings {
+			total += rating - players[i].Rating
+		}
+
+		assert.InDelta(t, 0, total, 1e-9)
+		assert.Greater(t, ratings[1], players[1].Rating)
+		assert.Less(t, ratings[0], players[0].Rating)
+	})
+
+	t.Run("should move a rating by at most the k factor", func(t *testing.T) {
+		players := []Player{{Rating: 1000, Placement: 1}}
+		for i := 0; i < 7; i++ {
+			players = append(players, Player{Rating: 2400, Placement: 2})
+		}
+
+		ratings := Update(players)
+
+		assert.LessOrEqual(t, ratings[0]-players[0].Rating, kFactor)
+	})
+
+	t.Run("should leave a player alone unrated", func(t *testing.T) {
+		assert.Equal(t, []float64{1432}, Update([]Player{{Rating: 1432, Placement: 1}}))
+	})
+
+	t.Run("should not move equal ratings that share a placement", func(t *testing.T) {
+		ratings := Update([]Player{
+			{Rating: Initial, Placement: 1},
+			{Rating: Initial, Placement: 1},
+		})
+
+		assert.Equal(t, []float64{Initial, Initial}, ratings)
+	})
+}
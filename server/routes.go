@@ -1,8 +1,10 @@
 package server
 
 import (
+	"expvar"
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/Maycon-Santos/go-snake-backend/container"
 	"github.com/Maycon-Santos/go-snake-backend/process"
@@ -41,10 +43,80 @@ func newRoutes(container container.Container) *httprouter.Router {
 	router.POST("/v1/signup", corsMiddleware(routes.SignUpHandler(container)))
 	router.GET("/v1/check_authentication", corsMiddleware(routes.CheckAuthentication(container)))
 	router.GET("/v1/get_account", corsMiddleware(authGetDataMiddleware(routes.GetAccount(container))))
+	router.GET("/v1/account/:id/stats", corsMiddleware(routes.GetAccountStats(container)))
+	router.GET("/v1/account/:id/matches", corsMiddleware(routes.ListAccountMatches(container)))
+	router.GET("/v1/leaderboard", corsMiddleware(leaderboardRoutes(authGetDataMiddleware, routes.GetLeaderboard(container))))
+	router.GET("/v1/matches", corsMiddleware(routes.ListMatches(container)))
+	router.GET("/v1/matches/lobby", corsMiddleware(routes.LobbySocket(container)))
 	router.POST("/v1/match/create", corsMiddleware(authGetDataMiddleware(routes.CreateMatch(container))))
-	router.GET("/v1/match/connect/:match_id", corsMiddleware(authGetDataMiddleware(routes.ConnectMatch(container))))
+	router.GET("/v1/match/:match_id/*action", corsMiddleware(matchRoutes(
+		authGetDataMiddleware(routes.ConnectMatch(container)),
+		routes.GetReplay(container),
+		routes.WatchReplay(container),
+	)))
+	router.POST("/v1/matchmaking/enqueue", corsMiddleware(authGetDataMiddleware(routes.EnqueueMatchmaking(container))))
+	router.POST("/v1/matchmaking/dequeue", corsMiddleware(authGetDataMiddleware(routes.DequeueMatchmaking(container))))
+	router.GET("/v1/matchmaking/status", corsMiddleware(authGetDataMiddleware(routes.MatchmakingStatus(container))))
 	router.GET("/v1/available_skins", corsMiddleware(routes.AvailableSkins(container)))
 	router.POST("/v1/update_skin", corsMiddleware(authGetDataMiddleware(routes.UpdateSkin(container))))
+	router.POST("/v1/maps", corsMiddleware(authGetDataMiddleware(routes.CreateMap(container))))
+	router.POST("/v1/maps/validate", corsMiddleware(authGetDataMiddleware(routes.ValidateMap(container))))
+	router.GET("/v1/maps", corsMiddleware(authGetDataMiddleware(routes.ListMaps(container))))
+	router.GET("/v1/maps/:map_id", corsMiddleware(authGetDataMiddleware(routes.GetMap(container))))
+	router.PUT("/v1/maps/:map_id", corsMiddleware(authGetDataMiddleware(routes.UpdateMap(container))))
+	router.PUT("/v1/maps/:map_id/publish", corsMiddleware(authGetDataMiddleware(routes.PublishMap(container))))
+	router.DELETE("/v1/maps/:map_id", corsMiddleware(authGetDataMiddleware(routes.DeleteMap(container))))
 
 	return router
 }
+
+// newMetricsRoutes serves the expvars. They show the command line and the
+// memory of the process, so they are kept off the public router and served
+// on the metrics address only.
+func newMetricsRoutes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	return mux
+}
+
+// matchRoutes serves every GET route under /v1/match/. httprouter does not
+// allow a wildcard to share a path segment with static routes, so these are
+// matched here instead:
+//
+//	/v1/match/connect/:match_id
+//	/v1/match/:match_id/replay
+//	/v1/match/replay/:replay_id/watch
+func matchRoutes(connectMatch, getReplay, watchReplay httprouter.Handle) httprouter.Handle {
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		segment := params.ByName("match_id")
+		action := strings.Split(strings.Trim(params.ByName("action"), "/"), "/")
+
+		switch {
+		case segment == "connect" && len(action) == 1:
+			connectMatch(writer, request, httprouter.Params{{Key: "match_id", Value: action[0]}})
+		case segment == "replay" && len(action) == 2 && action[1] == "watch":
+			watchReplay(writer, request, httprouter.Params{{Key: "replay_id", Value: action[0]}})
+		case len(action) == 1 && action[0] == "replay":
+			getReplay(writer, request, httprouter.Params{{Key: "match_id", Value: segment}})
+		default:
+			http.NotFound(writer, request)
+		}
+	}
+}
+
+// leaderboardRoutes asks for authentication only when the leaderboard is
+// requested around the player with ?around=me, keeping the rest of it
+// public.
+func leaderboardRoutes(authGetDataMiddleware func(next httprouter.Handle) httprouter.Handle, getLeaderboard httprouter.Handle) httprouter.Handle {
+	authenticated := authGetDataMiddleware(getLeaderboard)
+
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		if request.URL.Query().Get("around") == "me" {
+			authenticated(writer, request, params)
+			return
+		}
+
+		getLeaderboard(writer, request, params)
+	}
+}
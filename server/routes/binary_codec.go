@@ -0,0 +1,333 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Maycon-Santos/go-snake-backend/game"
+	"github.com/gorilla/websocket"
+)
+
+// binaryCodec is the compact wire format negotiated with binaryProtocol. It
+// has no field names: a message is written field by field, in the order of
+// the ordinals the `bin` tags give its fields, using these rules:
+//
+//	bool     1 byte, 0 or 1
+//	int      zig-zag varint
+//	uint     varint
+//	string   varint length followed by the UTF-8 bytes
+//	slice    varint length followed by every element
+//	pointer  1 byte, 0 when nil, otherwise 1 followed by the value
+//	struct   every exported field, by ordinal
+//
+// Every exported field of a struct must have an ordinal, numbered from 1
+// without gaps, so the layout never depends on how the fields are declared.
+// The layout is the schema of binaryProtocol: adding, removing or reordering
+// fields is a new schema, which takes a new protocol version.
+//
+// Varints follow encoding/binary. Incoming game.WrittenMessage frames use the
+// same rules, except that they may end before the fields with the highest
+// ordinals, which are then left empty.
+type binaryCodec struct{}
+
+// binaryLayouts caches the indexes of the fields of each struct type, by
+// ordinal.
+var binaryLayouts sync.Map
+
+// binaryLayout lists the indexes of the exported fields of a struct type in
+// the order of their ordinals.
+func binaryLayout(t reflect.Type) ([]int, error) {
+	if layout, ok := binaryLayouts.Load(t); ok {
+		return layout.([]int), nil
+	}
+
+	ordinals := make(map[int]int)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		ordinal, err := strconv.Atoi(field.Tag.Get("bin"))
+		if err != nil {
+			return nil, fmt.Errorf("binary codec: field %s of %s has no ordinal", field.Name, t.Name())
+		}
+
+		if _, ok := ordinals[ordinal]; ok {
+			return nil, fmt.Errorf("binary codec: ordinal %d of %s is taken twice", ordinal, t.Name())
+		}
+
+		ordinals[ordinal] = i
+	}
+
+	layout := make([]int, 0, len(ordinals))
+
+	for ordinal := 1; ordinal <= len(ordinals); ordinal++ {
+		i, ok := ordinals[ordinal]
+		if !ok {
+			return nil, fmt.Errorf("binary codec: ordinal %d of %s is missing", ordinal, t.Name())
+		}
+
+		layout = append(layout, i)
+	}
+
+	binaryLayouts.Store(t, layout)
+
+	return layout, nil
+}
+
+// binarySchema describes the layout of the messages of type t and of every
+// struct they hold, one struct per line, sorted by name.
+func binarySchema(t reflect.Type) (string, error) {
+	structs := make(map[string]string)
+
+	var describe func(t reflect.Type) (string, error)
+	describe = func(t reflect.Type) (string, error) {
+		switch t.Kind() {
+		case reflect.Ptr:
+			elem, err := describe(t.Elem())
+			return "*" + elem, err
+		case reflect.Slice:
+			elem, err := describe(t.Elem())
+			return "[]" + elem, err
+		case reflect.Struct:
+		default:
+			return t.Kind().String(), nil
+		}
+
+		if _, ok := structs[t.Name()]; ok {
+			return t.Name(), nil
+		}
+
+		structs[t.Name()] = ""
+
+		layout, err := binaryLayout(t)
+		if err != nil {
+			return "", err
+		}
+
+		fields := make([]string, 0, len(layout))
+
+		for n, i := range layout {
+			field, err := describe(t.Field(i).Type)
+			if err != nil {
+				return "", err
+			}
+
+			fields = append(fields, fmt.Sprintf("%d:%s %s", n+1, t.Field(i).Name, field))
+		}
+
+		structs[t.Name()] = fmt.Sprintf("%s { %s }", t.Name(), strings.Join(fields, "; "))
+
+		return t.Name(), nil
+	}
+
+	if _, err := describe(t); err != nil {
+		return "", err
+	}
+
+	lines := make([]string, 0, len(structs))
+	for _, line := range structs {
+		lines = append(lines, line)
+	}
+
+	sort.Strings(lines)
+
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+func (binaryCodec) MessageType() int {
+	return websocket.BinaryMessage
+}
+
+func (binaryCodec) Encode(message interface{}) ([]byte, error) {
+	return appendBinary(make([]byte, 0, 64), reflect.ValueOf(message))
+}
+
+func (binaryCodec) Decode(data []byte, message *game.WrittenMessage) error {
+	reader := bytes.NewReader(data)
+	value := reflect.ValueOf(message).Elem()
+
+	*message = game.WrittenMessage{}
+
+	layout, err := binaryLayout(value.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, i := range layout {
+		if reader.Len() == 0 {
+			break
+		}
+
+		if err := readBinary(reader, value.Field(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func appendBinary(buf []byte, value reflect.Value) ([]byte, error) {
+	var varint [binary.MaxVarintLen64]byte
+
+	switch value.Kind() {
+	case reflect.Bool:
+		if value.Bool() {
+			return append(buf, 1), nil
+		}
+
+		return append(buf, 0), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := binary.PutVarint(varint[:], value.Int())
+		return append(buf, varint[:n]...), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n := binary.PutUvarint(varint[:], value.Uint())
+		return append(buf, varint[:n]...), nil
+	case reflect.String:
+		n := binary.PutUvarint(varint[:], uint64(value.Len()))
+		buf = append(buf, varint[:n]...)
+		return append(buf, value.String()...), nil
+	case reflect.Slice:
+		n := binary.PutUvarint(varint[:], uint64(value.Len()))
+		buf = append(buf, varint[:n]...)
+
+		var err error
+
+		for i := 0; i < value.Len(); i++ {
+			if buf, err = appendBinary(buf, value.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+
+		return buf, nil
+	case reflect.Ptr:
+		if value.IsNil() {
+			return append(buf, 0), nil
+		}
+
+		return appendBinary(append(buf, 1), value.Elem())
+	case reflect.Struct:
+		layout, err := binaryLayout(value.Type())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, i := range layout {
+			if buf, err = appendBinary(buf, value.Field(i)); err != nil {
+				return nil, err
+			}
+		}
+
+		return buf, nil
+	}
+
+	return nil, fmt.Errorf("binary codec: unsupported kind %s", value.Kind())
+}
+
+func readBinary(reader *bytes.Reader, value reflect.Value) error {
+	switch value.Kind() {
+	case reflect.Bool:
+		b, err := reader.ReadByte()
+		if err != nil {
+			return err
+		}
+
+		value.SetBool(b != 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := binary.ReadVarint(reader)
+		if err != nil {
+			return err
+		}
+
+		value.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return err
+		}
+
+		value.SetUint(n)
+	case reflect.String:
+		length, err := readLength(reader)
+		if err != nil {
+			return err
+		}
+
+		str := make([]byte, length)
+		if _, err = reader.Read(str); err != nil && length > 0 {
+			return err
+		}
+
+		value.SetString(string(str))
+	case reflect.Slice:
+		length, err := readLength(reader)
+		if err != nil {
+			return err
+		}
+
+		slice := reflect.MakeSlice(value.Type(), length, length)
+
+		for i := 0; i < length; i++ {
+			if err = readBinary(reader, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+
+		value.Set(slice)
+	case reflect.Ptr:
+		present, err := reader.ReadByte()
+		if err != nil {
+			return err
+		}
+
+		if present == 0 {
+			value.Set(reflect.Zero(value.Type()))
+			return nil
+		}
+
+		elem := reflect.New(value.Type().Elem())
+		if err = readBinary(reader, elem.Elem()); err != nil {
+			return err
+		}
+
+		value.Set(elem)
+	case reflect.Struct:
+		layout, err := binaryLayout(value.Type())
+		if err != nil {
+			return err
+		}
+
+		for _, i := range layout {
+			if err := readBinary(reader, value.Field(i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("binary codec: unsupported kind %s", value.Kind())
+	}
+
+	return nil
+}
+
+// readLength reads the length of a string or slice, which can never be
+// greater than what is left to read.
+func readLength(reader *bytes.Reader) (int, error) {
+	length, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return 0, err
+	}
+
+	if length > uint64(reader.Len()) {
+		return 0, fmt.Errorf("binary codec: length %d is greater than the message", length)
+	}
+
+	return int(length), nil
+}
@@ -0,0 +1,122 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/Maycon-Santos/go-snake-backend/game"
+	"github.com/Maycon-Santos/go-snake-backend/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBinaryCodec(t *testing.T) {
+	codec := binaryCodec{}
+
+	t.Run("should decode the same message it encoded", func(t *testing.T) {
+		msg := message{
+			Player: newPlayerMessage(
+				"1",
+				"michael",
+				[]game.BodyFragment{{X: 3, Y: 2}, {X: 2, Y: 2}, {X: -1, Y: 2}},
+				true,
+				false,
+			),
+			RemovePlayer: "2",
+		}
+
+		data, err := codec.Encode(msg)
+		assert.NoError(t, err)
+
+		var decoded message
+		assert.NoError(t, readBinary(bytes.NewReader(data), reflect.ValueOf(&decoded).Elem()))
+
+		assert.Equal(t, msg, decoded)
+	})
+
+	t.Run("should be smaller than JSON", func(t *testing.T) {
+		msg := parseSnapshotMessage(game.Snapshot{
+			Seq: 10,
+			Snakes: []game.SnakeDelta{{
+				PlayerID: "1",
+				Alive:    true,
+				Head:     []game.BodyFragment{{X: 10, Y: 4}},
+				Tail:     1,
+			}},
+		})
+
+		binaryData, err := codec.Encode(msg)
+		assert.NoError(t, err)
+
+		jsonData, err := game.JSONCodec.Encode(msg)
+		assert.NoError(t, err)
+
+		assert.Less(t, len(binaryData), len(jsonData)/3)
+	})
+
+	t.Run("should decode a written message", func(t *testing.T) {
+		data := []byte{5, 'r', 'i', 'g', 'h', 't', 1, 1}
+
+		var writtenMessage game.WrittenMessage
+		assert.NoError(t, codec.Decode(data, &writtenMessage))
+
+		assert.Equal(t, game.WrittenMessage{MoveTo: "right", Ready: utils.Ptr(true)}, writtenMessage)
+	})
+
+	t.Run("should decode the team a written message assigns", func(t *testing.T) {
+		data := []byte{0, 0, 1, 1, '2', 4, 'b', 'l', 'u', 'e', 0}
+
+		var writtenMessage game.WrittenMessage
+		assert.NoError(t, codec.Decode(data, &writtenMessage))
+
+		assert.Equal(t, game.WrittenMessage{Team: &game.TeamAssignment{PlayerID: "2", Team: "blue"}}, writtenMessage)
+	})
+
+	t.Run("should keep the layout of its protocol version", func(t *testing.T) {
+		golden, err := os.ReadFile("testdata/" + binaryProtocol + ".schema")
+		assert.NoError(t, err)
+
+		outgoing, err := binarySchema(reflect.TypeOf(message{}))
+		assert.NoError(t, err)
+
+		incoming, err := binarySchema(reflect.TypeOf(game.WrittenMessage{}))
+		assert.NoError(t, err)
+
+		assert.Equal(t, string(golden), outgoing+incoming, "the layout changed: bump binaryProtocol and add its schema to testdata")
+	})
+
+	t.Run("should encode the bytes of its protocol version", func(t *testing.T) {
+		data, err := codec.Encode(message{
+			Player: &playerMessage{
+				ID:        "1",
+				Username:  "ana",
+				Body:      []bodyFragmentMessage{{X: 2, Y: -1}},
+				Alive:     true,
+				Connected: true,
+				Team:      "red",
+				TeamColor: "#f00",
+				Effects:   []effectMessage{{Kind: "GHOST", Until: 300}},
+			},
+			Countdown: &countdownMessage{Remaining: 3},
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, "0001013103616e61010401000101037265640423663030010547484f5354ac020000000000000000000106", hex.EncodeToString(data))
+	})
+
+	t.Run("should refuse a struct without ordinals", func(t *testing.T) {
+		_, err := codec.Encode(struct {
+			Seq uint64
+		}{Seq: 1})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("should return an error on a truncated message", func(t *testing.T) {
+		var writtenMessage game.WrittenMessage
+
+		assert.Error(t, codec.Decode([]byte{5, 'r', 'i'}, &writtenMessage))
+	})
+}
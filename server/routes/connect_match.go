@@ -1,7 +1,10 @@
 package routes
 
 import (
+	"context"
+	"crypto/subtle"
 	"log"
+	"log/slog"
 	"net/http"
 
 	"github.com/Maycon-Santos/go-snake-backend/container"
@@ -27,6 +30,14 @@ func ConnectMatch(container container.Container) httprouter.Handle {
 		accountUsername := params.ByName("account_username")
 		matchID := params.ByName("match_id")
 
+		// The socket outlives the request, so errors are handled with a
+		// context that is not canceled when the handler returns.
+		ctx := withLogAttrs(
+			context.WithoutCancel(request.Context()),
+			slog.String("match_id", matchID),
+			slog.String("player_id", accountID),
+		)
+
 		match, err := matches.GetMatchByID(matchID)
 		if err != nil {
 			makeResponse(request.Context(), writer, responseConfig{
@@ -42,153 +53,188 @@ func ConnectMatch(container container.Container) httprouter.Handle {
 			return
 		}
 
-		upgrader := websocket.Upgrader{
-			ReadBufferSize:  1024,
-			WriteBufferSize: 1024,
-			CheckOrigin:     func(r *http.Request) bool { return true },
+		spectating := request.URL.Query().Get("role") == "spectator"
+
+		if !spectating && match.GetPlayerByID(accountID) == nil && !match.Admits(accountID) {
+			makeResponse(request.Context(), writer, responseConfig{
+				Header: responseHeader{
+					Status: http.StatusForbidden,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_MATCH_RESERVED,
+					Message: "room is reserved to other players",
+				},
+			})
+			return
 		}
 
-		socket, err := upgrader.Upgrade(writer, request, nil)
+		socket, err := upgradeSocket(writer, request)
 		if err != nil {
-			handleError(request.Context(), err)
+			handleError(ctx, err)
+			return
+		}
+
+		codec := socketCodec(socket)
+
+		if spectating {
+			watchMatch(ctx, match, socket, codec, accountID, accountUsername, skinsRepository)
+			return
 		}
 
 		var currentPlayer game.Player
 
 		if player := match.GetPlayerByID(accountID); player != nil {
 			currentPlayer = *player
-			currentPlayer.SetSocket(socket)
-		} else {
-			currentPlayer = game.NewPlayer(accountID, accountUsername)
-			currentPlayer.SetSocket(socket)
-
-			match.OnStart(func() {
-				for _, food := range match.GetFoods() {
-					food.OnUpdateState(func() {
-						msgBytes, err := parseFoodMessage(food)
-						if err != nil {
-							handleError(request.Context(), err)
-						}
-
-						err = match.SendMessage(msgBytes)
-						if err != nil {
-							handleError(request.Context(), err)
-						}
-					})
-				}
-			})
 
-			currentPlayer.OnUpdateState(func() {
-				msgBytes, err := parsePlayerMessage(currentPlayer)
-				if err != nil {
-					handleError(request.Context(), err)
-					return
+			resumeToken := request.URL.Query().Get("resume")
+			if subtle.ConstantTimeCompare([]byte(resumeToken), []byte(currentPlayer.GetResumeToken())) != 1 {
+				closeMessage := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "invalid resume token")
+				if err = socket.WriteMessage(websocket.CloseMessage, closeMessage); err != nil {
+					handleError(ctx, err)
 				}
 
-				match.SendMessage(msgBytes)
-			})
-
-			if err = match.Enter(currentPlayer); err != nil {
-				handleError(request.Context(), err)
+				socket.Close()
 				return
 			}
-		}
 
-		socket.SetCloseHandler(func(code int, text string) (err error) {
-			if match.GetStatus() == game.StatusOnHold {
-				match.RemovePlayer(currentPlayer)
+			currentPlayer.SetSocket(socket, codec)
 
-				removePlayerMessageBytes, err := parseRemovePlayer(currentPlayer)
-				if err != nil {
-					handleError(request.Context(), err)
-				}
+			if err = match.Resume(currentPlayer); err != nil {
+				handleError(ctx, err)
+				socket.Close()
+				return
+			}
+		} else {
+			currentPlayer = game.NewPlayer(accountID, accountUsername)
 
-				if match.SendMessage(removePlayerMessageBytes); err != nil {
-					handleError(request.Context(), err)
+			currentPlayer.OnUpdateState(func() {
+				if err := match.SendMessage(parsePlayerMessage(currentPlayer)); err != nil {
+					handleError(ctx, err)
 				}
-			}
+			})
 
-			if len(match.GetPlayers()) == 0 {
-				matches.DeleteByID(matchID)
+			if err = match.Enter(currentPlayer); err != nil {
+				handleError(ctx, err)
+				socket.Close()
+				return
 			}
 
-			return
-		})
+			go logErrors(ctx, currentPlayer, match.Done())
 
-		matchMessageBytes, err := parseMatchMessage(match)
-		if err != nil {
-			handleError(request.Context(), err)
+			currentPlayer.SetSocket(socket, codec)
 		}
 
-		if err = currentPlayer.SendMessage(matchMessageBytes); err != nil {
-			handleError(request.Context(), err)
+		if err = currentPlayer.SendMessage(parseMatchMessage(match)); err != nil {
+			handleError(ctx, err)
 		}
 
-		if match.GetStatus() != game.StatusRunning {
-			currentPlayerMessageBytes, err := parsePlayerMessage(currentPlayer)
-			if err != nil {
-				handleError(request.Context(), err)
-			}
+		if err = currentPlayer.SendMessage(parseSessionMessage(currentPlayer, match.GetReconnectGrace())); err != nil {
+			handleError(ctx, err)
+		}
 
-			for _, player := range match.GetPlayers() {
-				playerMessageBytes, err := parsePlayerMessage(player)
-				if err != nil {
-					handleError(request.Context(), err)
-				}
+		if !match.GetStatus().IsPlaying() {
+			currentPlayerMessage := parsePlayerMessage(currentPlayer)
 
-				if err = currentPlayer.SendMessage(playerMessageBytes); err != nil {
-					handleError(request.Context(), err)
+			for _, player := range match.GetPlayers() {
+				if err = currentPlayer.SendMessage(parsePlayerMessage(player)); err != nil {
+					handleError(ctx, err)
 				}
 
 				if player.GetID() != currentPlayer.GetID() {
-					if err = player.SendMessage(currentPlayerMessageBytes); err != nil {
-						handleError(request.Context(), err)
+					if err = player.SendMessage(currentPlayerMessage); err != nil {
+						handleError(ctx, err)
 					}
 				}
 			}
 		}
 
+		currentPlayerSkin, err := skinsRepository.GetAccountSkin(ctx, currentPlayer.GetID())
+		if err != nil {
+			handleError(ctx, err)
+		}
+
 		for _, player := range match.GetPlayers() {
-			currentPlayerSkin, err := skinsRepository.GetAccountSkin(request.Context(), currentPlayer.GetID())
+			playerSkin, err := skinsRepository.GetAccountSkin(ctx, player.GetID())
 			if err != nil {
-				handleError(request.Context(), err)
+				handleError(ctx, err)
 			}
 
-			currentPlayerSkinMessageBytes, err := parsePlayerSkin(currentPlayer, *currentPlayerSkin)
-			if err != nil {
-				handleError(request.Context(), err)
+			if playerSkin != nil {
+				if err = currentPlayer.SendMessage(parsePlayerSkin(player.GetID(), *playerSkin)); err != nil {
+					handleError(ctx, err)
+				}
 			}
 
-			playerSkin, err := skinsRepository.GetAccountSkin(request.Context(), player.GetID())
-			if err != nil {
-				handleError(request.Context(), err)
+			if currentPlayerSkin != nil && player.GetID() != currentPlayer.GetID() {
+				if err = player.SendMessage(parsePlayerSkin(currentPlayer.GetID(), *currentPlayerSkin)); err != nil {
+					handleError(ctx, err)
+				}
 			}
+		}
 
-			playerSkinMessageBytes, err := parsePlayerSkin(player, *playerSkin)
-			if err != nil {
-				handleError(request.Context(), err)
+		for _, food := range match.GetFoods() {
+			if err = match.SendMessage(parseFoodMessage(food)); err != nil {
+				handleError(ctx, err)
 			}
+		}
+	}
+}
 
-			if err = currentPlayer.SendMessage(playerSkinMessageBytes); err != nil {
-				handleError(request.Context(), err)
-			}
+func watchMatch(
+	ctx context.Context,
+	match game.Match,
+	socket *websocket.Conn,
+	codec game.Codec,
+	accountID string,
+	accountUsername string,
+	skinsRepository db.SkinsRepository,
+) {
+	var spectator game.Player
+
+	if s := match.GetSpectatorByID(accountID); s != nil {
+		spectator = *s
+	} else {
+		spectator = game.NewSpectator(accountID, accountUsername)
+
+		if err := match.Watch(spectator); err != nil {
+			handleError(ctx, err)
+			socket.Close()
+			return
+		}
 
-			if player.GetID() != currentPlayer.GetID() {
-				if err = player.SendMessage(currentPlayerSkinMessageBytes); err != nil {
-					handleError(request.Context(), err)
-				}
-			}
+		go logErrors(ctx, spectator, match.Done())
+	}
+
+	spectator.SetSocket(socket, codec)
+
+	if err := match.SendMessage(parseMatchMessage(match)); err != nil {
+		handleError(ctx, err)
+	}
+
+	for _, player := range match.GetPlayers() {
+		if err := spectator.SendMessage(parsePlayerMessage(player)); err != nil {
+			handleError(ctx, err)
 		}
 
-		for _, food := range match.GetFoods() {
-			foodMessageBytes, err := parseFoodMessage(food)
-			if err != nil {
-				handleError(request.Context(), err)
-			}
+		playerSkin, err := skinsRepository.GetAccountSkin(ctx, player.GetID())
+		if err != nil {
+			handleError(ctx, err)
+			continue
+		}
 
-			if err = match.SendMessage(foodMessageBytes); err != nil {
-				handleError(request.Context(), err)
-			}
+		if playerSkin == nil {
+			continue
+		}
+
+		if err = spectator.SendMessage(parsePlayerSkin(player.GetID(), *playerSkin)); err != nil {
+			handleError(ctx, err)
+		}
+	}
+
+	for _, food := range match.GetFoods() {
+		if err := spectator.SendMessage(parseFoodMessage(food)); err != nil {
+			handleError(ctx, err)
 		}
 	}
 }
@@ -0,0 +1,106 @@
+package routes
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/Maycon-Santos/go-snake-backend/container"
+	"github.com/Maycon-Santos/go-snake-backend/db"
+	"github.com/julienschmidt/httprouter"
+)
+
+func CreateMap(container container.Container) httprouter.Handle {
+	var mapsRepository db.MapsRepository
+
+	err := container.Retrieve(&mapsRepository)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		accountID := params.ByName("account_id")
+
+		var requestBody mapRequestBody
+
+		if err := json.NewDecoder(request.Body).Decode(&requestBody); err != nil {
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusUnprocessableEntity,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_PAYLOAD_INVALID,
+					Message: "payload is invalid",
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(request.Context(), err)
+			}
+
+			return
+		}
+
+		_map, responseType, err := parseMapRequestBody(requestBody)
+		if err != nil {
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusForbidden,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    responseType,
+					Message: err.Error(),
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(request.Context(), err)
+			}
+
+			return
+		}
+
+		storedMap, err := saveMap(request.Context(), mapsRepository, "", accountID, requestBody.PlayersLimit, _map)
+		if err != nil {
+			handleError(request.Context(), err)
+
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusInternalServerError,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_UNKNOWN,
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(request.Context(), err)
+			}
+
+			return
+		}
+
+		result, err := parseMapResponseResult(*storedMap)
+		if err != nil {
+			handleError(request.Context(), err)
+			return
+		}
+
+		response := responseConfig{
+			Header: responseHeader{
+				Status: http.StatusCreated,
+			},
+			Body: responseBody{
+				Success: true,
+				Result:  result,
+			},
+		}
+
+		if err := makeResponse(request.Context(), writer, response); err != nil {
+			handleError(request.Context(), err)
+		}
+	}
+}
@@ -2,67 +2,247 @@ package routes
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 
 	"github.com/Maycon-Santos/go-snake-backend/container"
+	"github.com/Maycon-Santos/go-snake-backend/db"
 	"github.com/Maycon-Santos/go-snake-backend/game"
-	"github.com/Maycon-Santos/go-snake-backend/process"
-	"github.com/Maycon-Santos/go-snake-backend/utils"
 	"github.com/julienschmidt/httprouter"
 )
 
+// createMatchRequestBody describes the match to create. When Map names one
+// of the maps shipped with the server, or MapID one made in the map editor,
+// its size, edges and walls are used instead of MapWidth, MapHeight and
+// Edges. Public matches are listed in the lobby for anyone to join; the
+// others are joined by sharing their ID.
+type createMatchRequestBody struct {
+	PlayersLimit  int    `json:"players_limit"`
+	Map           string `json:"map"`
+	MapID         string `json:"map_id"`
+	MapWidth      int    `json:"map_width"`
+	MapHeight     int    `json:"map_height"`
+	FoodsLimit    int    `json:"foods_limit"`
+	TickRate      int    `json:"tick_rate"`
+	Edges         string `json:"edges"`
+	InitialLength int    `json:"initial_length"`
+	TimeLimit     int    `json:"time_limit"`
+	Countdown     int    `json:"countdown"`
+	SuddenDeath   int    `json:"sudden_death"`
+	Results       int    `json:"results"`
+	Series        int    `json:"series"`
+	Teams         int    `json:"teams"`
+	FriendlyFire  bool   `json:"friendly_fire"`
+	Shrink        int    `json:"shrink"`
+	ShrinkWarning int    `json:"shrink_warning"`
+	MinArena      int    `json:"min_arena"`
+	Items         int    `json:"items"`
+	HeadOn        string `json:"head_on"`
+	TailChasing   bool   `json:"tail_chasing"`
+	Public        bool   `json:"public"`
+}
+
+// Every field of the request body is optional; the ones left out keep these
+// values.
+var defaultCreateMatchRequestBody = createMatchRequestBody{
+	PlayersLimit:  5,
+	MapWidth:      64,
+	MapHeight:     36,
+	FoodsLimit:    1,
+	TickRate:      18,
+	Edges:         "wrap",
+	InitialLength: 3,
+	TimeLimit:     0,
+	Countdown:     3,
+	SuddenDeath:   0,
+	Results:       5,
+	Series:        1,
+	Teams:         0,
+	FriendlyFire:  false,
+	Shrink:        0,
+	ShrinkWarning: 3,
+	MinArena:      10,
+	Items:         0,
+	HeadOn:        "both_die",
+	TailChasing:   true,
+	Public:        false,
+}
+
 type createRoomResponseResult struct {
 	MatchID string `json:"match_id"`
 }
 
 func CreateMatch(container container.Container) httprouter.Handle {
 	var (
-		env     process.Env
-		matches game.Matches
+		matches        game.Matches
+		mapsRepository db.MapsRepository
 	)
 
-	err := container.Retrieve(&env, &matches)
+	err := container.Retrieve(&matches, &mapsRepository)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	services := retrieveMatchServices(container)
+
 	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 		accountID := params.ByName("account_id")
 
-		if match, err := matches.GetMatchByOwnerID(accountID); err == nil {
-			matches.DeleteByID(match.GetID())
-		}
+		requestBody := defaultCreateMatchRequestBody
+
+		if err := json.NewDecoder(request.Body).Decode(&requestBody); err != nil && err != io.EOF {
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusUnprocessableEntity,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_PAYLOAD_INVALID,
+					Message: "payload is invalid",
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(request.Context(), err)
+			}
 
-		match, err := matches.Add(5)
-		if err != nil {
-			handleError(request.Context(), err)
 			return
 		}
 
-		match.UpdateState(game.MatchStateInput{
-			Status:     utils.Ptr(game.StatusOnHold),
-			FoodsLimit: utils.Ptr(1),
-			Map: &game.MapInput{
-				Tiles: &game.Tiles{
-					Horizontal: 64,
-					Vertical:   36,
+		if responseType, err := validateCreateMatchFields(requestBody); err != nil {
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusForbidden,
 				},
+				Body: responseBody{
+					Success: false,
+					Type:    responseType,
+					Message: err.Error(),
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(request.Context(), err)
+			}
+
+			return
+		}
+
+		edges := game.EdgesWrap
+		if requestBody.Edges == "wall" {
+			edges = game.EdgesWall
+		}
+
+		selectedMap := game.Map{
+			Tiles: game.Tiles{
+				Horizontal: requestBody.MapWidth,
+				Vertical:   requestBody.MapHeight,
 			},
-		})
+			Edges: edges,
+		}
+
+		if _map, ok := game.GetMapDefinition(requestBody.Map); ok {
+			selectedMap = _map
+		}
 
-		match.OnUpdateState(func() {
-			msgBytes, err := parseMatchMessage(match)
+		if requestBody.MapID != "" {
+			storedMap, err := mapsRepository.GetByID(request.Context(), requestBody.MapID)
 			if err != nil {
 				handleError(request.Context(), err)
+
+				response := responseConfig{
+					Header: responseHeader{
+						Status: http.StatusInternalServerError,
+					},
+					Body: responseBody{
+						Success: false,
+						Type:    TYPE_UNKNOWN,
+					},
+				}
+
+				if err := makeResponse(request.Context(), writer, response); err != nil {
+					handleError(request.Context(), err)
+				}
+
+				return
+			}
+
+			if !canUseMap(storedMap, accountID) {
+				response := responseConfig{
+					Header: responseHeader{
+						Status: http.StatusNotFound,
+					},
+					Body: responseBody{
+						Success: false,
+						Type:    TYPE_MAP_NOT_FOUND,
+						Message: "map not found",
+					},
+				}
+
+				if err := makeResponse(request.Context(), writer, response); err != nil {
+					handleError(request.Context(), err)
+				}
+
 				return
 			}
 
-			err = match.SendMessage(msgBytes)
+			_map, err := decodeMapDefinition(*storedMap)
 			if err != nil {
 				handleError(request.Context(), err)
+
+				response := responseConfig{
+					Header: responseHeader{
+						Status: http.StatusInternalServerError,
+					},
+					Body: responseBody{
+						Success: false,
+						Type:    TYPE_UNKNOWN,
+					},
+				}
+
+				if err := makeResponse(request.Context(), writer, response); err != nil {
+					handleError(request.Context(), err)
+				}
+
+				return
+			}
+
+			selectedMap = _map
+		}
+
+		selectedMap = playedMap(requestBody, selectedMap)
+
+		if responseType, err := validatePlayedMap(requestBody, selectedMap); err != nil {
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusForbidden,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    responseType,
+					Message: err.Error(),
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(request.Context(), err)
 			}
-		})
+
+			return
+		}
+
+		if match, err := matches.GetMatchByOwnerID(accountID); err == nil {
+			matches.DeleteByID(match.GetID())
+		}
+
+		match, err := services.newMatch(customMatchMode, requestBody, selectedMap)
+		if err != nil {
+			handleError(request.Context(), err)
+			return
+		}
 
 		err = makeResponse(context.Background(), writer, responseConfig{
 			Body: responseBody{
@@ -78,3 +258,101 @@ func CreateMatch(container container.Container) httprouter.Handle {
 		}
 	}
 }
+
+// validatePlayedMap checks the settings that depend on the map the match is
+// played on, which is only known once a stored map is read.
+func validatePlayedMap(requestBody createMatchRequestBody, selectedMap game.Map) (responseType, error) {
+	if requestBody.Shrink > 0 {
+		side := min(selectedMap.Tiles.Horizontal, selectedMap.Tiles.Vertical)
+
+		// The arena loses a ring of tiles on every side at once, so it
+		// needs two tiles more than the min arena to shrink at all.
+		if requestBody.MinArena > side-2 {
+			return TYPE_MIN_ARENA_ABOVE_MAP, fmt.Errorf("The min arena (%d) leaves no room to shrink a map of %dx%d", requestBody.MinArena, selectedMap.Tiles.Horizontal, selectedMap.Tiles.Vertical)
+		}
+	}
+
+	return validateMapLayout(selectedMap, requestBody.PlayersLimit, requestBody.InitialLength)
+}
+
+func validateCreateMatchFields(requestBody createMatchRequestBody) (responseType, error) {
+	if errType, err := playersLimitValidator.Validate(requestBody.PlayersLimit); err != nil {
+		return playersLimitResponseErrors[errType], err
+	}
+
+	if requestBody.Map != "" {
+		if errType, err := mapNameValidator.Validate(requestBody.Map); err != nil {
+			return mapNameResponseErrors[errType], err
+		}
+	}
+
+	if errType, err := mapWidthValidator.Validate(requestBody.MapWidth); err != nil {
+		return mapWidthResponseErrors[errType], err
+	}
+
+	if errType, err := mapHeightValidator.Validate(requestBody.MapHeight); err != nil {
+		return mapHeightResponseErrors[errType], err
+	}
+
+	if errType, err := foodsLimitValidator.Validate(requestBody.FoodsLimit); err != nil {
+		return foodsLimitResponseErrors[errType], err
+	}
+
+	if errType, err := tickRateValidator.Validate(requestBody.TickRate); err != nil {
+		return tickRateResponseErrors[errType], err
+	}
+
+	if errType, err := edgesValidator.Validate(requestBody.Edges); err != nil {
+		return edgesResponseErrors[errType], err
+	}
+
+	if errType, err := initialLengthValidator.Validate(requestBody.InitialLength); err != nil {
+		return initialLengthResponseErrors[errType], err
+	}
+
+	if errType, err := timeLimitValidator.Validate(requestBody.TimeLimit); err != nil {
+		return timeLimitResponseErrors[errType], err
+	}
+
+	if errType, err := countdownValidator.Validate(requestBody.Countdown); err != nil {
+		return countdownResponseErrors[errType], err
+	}
+
+	if errType, err := suddenDeathValidator.Validate(requestBody.SuddenDeath); err != nil {
+		return suddenDeathResponseErrors[errType], err
+	}
+
+	if errType, err := resultsValidator.Validate(requestBody.Results); err != nil {
+		return resultsResponseErrors[errType], err
+	}
+
+	if errType, err := seriesValidator.Validate(requestBody.Series); err != nil {
+		return seriesResponseErrors[errType], err
+	}
+
+	if errType, err := teamsValidator.Validate(requestBody.Teams); err != nil {
+		return teamsResponseErrors[errType], err
+	}
+
+	if errType, err := shrinkValidator.Validate(requestBody.Shrink); err != nil {
+		return shrinkResponseErrors[errType], err
+	}
+
+	if errType, err := shrinkWarningValidator.Validate(requestBody.ShrinkWarning); err != nil {
+		return shrinkWarningResponseErrors[errType], err
+	}
+
+	if errType, err := minArenaValidator.Validate(requestBody.MinArena); err != nil {
+		return minArenaResponseErrors[errType], err
+	}
+
+	if errType, err := itemsValidator.Validate(requestBody.Items); err != nil {
+		return itemsResponseErrors[errType], err
+	}
+
+	if errType, err := headOnValidator.Validate(requestBody.HeadOn); err != nil {
+		return headOnResponseErrors[errType], err
+	}
+
+	return TYPE_UNKNOWN, nil
+}
@@ -0,0 +1,156 @@
+package routes
+
+import (
+	"testing"
+
+	"github.com/Maycon-Santos/go-snake-backend/game"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCreateMatchFields(t *testing.T) {
+	t.Run("should accept the default options", func(t *testing.T) {
+		_, err := validateCreateMatchFields(defaultCreateMatchRequestBody)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("should response an error when `players_limit` field is greater than 9", func(t *testing.T) {
+		requestBody := defaultCreateMatchRequestBody
+		requestBody.PlayersLimit = 10
+
+		responseType, err := validateCreateMatchFields(requestBody)
+
+		assert.Error(t, err)
+		assert.Equal(t, TYPE_PLAYERS_LIMIT_ABOVE_MAX, responseType)
+	})
+
+	t.Run("should response an error when `map_width` field is less than 32", func(t *testing.T) {
+		requestBody := defaultCreateMatchRequestBody
+		requestBody.MapWidth = 16
+
+		responseType, err := validateCreateMatchFields(requestBody)
+
+		assert.Error(t, err)
+		assert.Equal(t, TYPE_MAP_WIDTH_BELOW_MIN, responseType)
+	})
+
+	t.Run("should response an error when `edges` field is unknown", func(t *testing.T) {
+		requestBody := defaultCreateMatchRequestBody
+		requestBody.Edges = "bounce"
+
+		responseType, err := validateCreateMatchFields(requestBody)
+
+		assert.EqualError(t, err, "the edges field must be one of the following: wrap, wall")
+		assert.Equal(t, TYPE_EDGES_INVALID, responseType)
+	})
+
+	t.Run("should response an error when `time_limit` field is negative", func(t *testing.T) {
+		requestBody := defaultCreateMatchRequestBody
+		requestBody.TimeLimit = -1
+
+		responseType, err := validateCreateMatchFields(requestBody)
+
+		assert.Error(t, err)
+		assert.Equal(t, TYPE_TIME_LIMIT_BELOW_MIN, responseType)
+	})
+
+	t.Run("should response an error when `countdown` field is above the max", func(t *testing.T) {
+		requestBody := defaultCreateMatchRequestBody
+		requestBody.Countdown = 11
+
+		responseType, err := validateCreateMatchFields(requestBody)
+
+		assert.Error(t, err)
+		assert.Equal(t, TYPE_COUNTDOWN_ABOVE_MAX, responseType)
+	})
+
+	t.Run("should response an error when `min_arena` field is below the min", func(t *testing.T) {
+		requestBody := defaultCreateMatchRequestBody
+		requestBody.Shrink = 10
+		requestBody.MinArena = 2
+
+		responseType, err := validateCreateMatchFields(requestBody)
+
+		assert.Error(t, err)
+		assert.Equal(t, TYPE_MIN_ARENA_BELOW_MIN, responseType)
+	})
+
+	t.Run("should response an error when `items` field is above the max", func(t *testing.T) {
+		requestBody := defaultCreateMatchRequestBody
+		requestBody.Items = 6
+
+		responseType, err := validateCreateMatchFields(requestBody)
+
+		assert.Error(t, err)
+		assert.Equal(t, TYPE_ITEMS_ABOVE_MAX, responseType)
+	})
+
+	t.Run("should response an error when `map` field is unknown", func(t *testing.T) {
+		requestBody := defaultCreateMatchRequestBody
+		requestBody.Map = "labyrinth"
+
+		responseType, err := validateCreateMatchFields(requestBody)
+
+		assert.Error(t, err)
+		assert.Equal(t, TYPE_MAP_NOT_FOUND, responseType)
+	})
+
+	t.Run("should response an error when `head_on` field is unknown", func(t *testing.T) {
+		requestBody := defaultCreateMatchRequestBody
+		requestBody.HeadOn = "shorter_wins"
+
+		responseType, err := validateCreateMatchFields(requestBody)
+
+		assert.EqualError(t, err, "the head_on field must be one of the following: both_die, longer_wins")
+		assert.Equal(t, TYPE_HEAD_ON_INVALID, responseType)
+	})
+}
+
+func TestPlayedMap(t *testing.T) {
+	selectedMap := game.Map{Tiles: game.Tiles{Horizontal: 20, Vertical: 10}, Edges: game.EdgesWrap}
+
+	t.Run("should wall a shrinking arena before it is validated", func(t *testing.T) {
+		settings := defaultCreateMatchRequestBody
+		settings.Shrink = 10
+
+		assert.Equal(t, game.EdgesWall, playedMap(settings, selectedMap).Edges)
+	})
+
+	t.Run("should keep the edges of the map otherwise", func(t *testing.T) {
+		assert.Equal(t, game.EdgesWrap, playedMap(defaultCreateMatchRequestBody, selectedMap).Edges)
+	})
+}
+
+func TestValidatePlayedMap(t *testing.T) {
+	selectedMap := game.Map{Tiles: game.Tiles{Horizontal: 64, Vertical: 36}, Edges: game.EdgesWall}
+
+	t.Run("should reject a min arena the map is too small to shrink to", func(t *testing.T) {
+		settings := defaultCreateMatchRequestBody
+		settings.Shrink = 10
+		settings.MinArena = 64
+
+		responseType, err := validatePlayedMap(settings, selectedMap)
+
+		assert.Error(t, err)
+		assert.Equal(t, TYPE_MIN_ARENA_ABOVE_MAP, responseType)
+	})
+
+	t.Run("should accept a min arena the map can shrink to", func(t *testing.T) {
+		settings := defaultCreateMatchRequestBody
+		settings.Shrink = 10
+		settings.MinArena = 34
+
+		_, err := validatePlayedMap(settings, selectedMap)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("should not check the min arena of a match that does not shrink", func(t *testing.T) {
+		settings := defaultCreateMatchRequestBody
+		settings.MinArena = 64
+
+		_, err := validatePlayedMap(settings, selectedMap)
+
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,76 @@
+package routes
+
+import (
+	"log"
+	"log/slog"
+	"net/http"
+
+	"github.com/Maycon-Santos/go-snake-backend/container"
+	"github.com/Maycon-Santos/go-snake-backend/db"
+	"github.com/julienschmidt/httprouter"
+)
+
+func DeleteMap(container container.Container) httprouter.Handle {
+	var mapsRepository db.MapsRepository
+
+	err := container.Retrieve(&mapsRepository)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		accountID := params.ByName("account_id")
+		mapID := params.ByName("map_id")
+		ctx := withLogAttrs(request.Context(), slog.String("map_id", mapID))
+
+		deleted, err := mapsRepository.Delete(request.Context(), mapID, accountID)
+		if err != nil {
+			handleError(ctx, err)
+
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusInternalServerError,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_UNKNOWN,
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(ctx, err)
+			}
+
+			return
+		}
+
+		if !deleted {
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusNotFound,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_MAP_NOT_FOUND,
+					Message: "map not found",
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(ctx, err)
+			}
+
+			return
+		}
+
+		response := responseConfig{
+			Body: responseBody{
+				Success: true,
+			},
+		}
+
+		if err := makeResponse(request.Context(), writer, response); err != nil {
+			handleError(ctx, err)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package routes
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/Maycon-Santos/go-snake-backend/container"
+	"github.com/Maycon-Santos/go-snake-backend/matchmaking"
+	"github.com/julienschmidt/httprouter"
+)
+
+// DequeueMatchmaking takes the account out of the matchmaking queue.
+func DequeueMatchmaking(container container.Container) httprouter.Handle {
+	var queue matchmaking.Queue
+
+	err := container.Retrieve(&queue)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		accountID := params.ByName("account_id")
+
+		if !queue.Dequeue(accountID) {
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusNotFound,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_MATCHMAKING_NOT_QUEUED,
+					Message: "account is not queued",
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(request.Context(), err)
+			}
+
+			return
+		}
+
+		response := responseConfig{
+			Body: responseBody{
+				Success: true,
+			},
+		}
+
+		if err := makeResponse(request.Context(), writer, response); err != nil {
+			handleError(request.Context(), err)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Maycon-Santos/go-snake-backend/container"
+	"github.com/Maycon-Santos/go-snake-backend/db"
+	"github.com/Maycon-Santos/go-snake-backend/game"
+	"github.com/Maycon-Santos/go-snake-backend/matchmaking"
+	"github.com/Maycon-Santos/go-snake-backend/rating"
+	"github.com/julienschmidt/httprouter"
+)
+
+type enqueueMatchmakingRequestBody struct {
+	Mode   string `json:"mode"`
+	Region string `json:"region"`
+}
+
+func matchmakingModeNames() []string {
+	names := make([]string, 0, len(matchmaking.DefaultModes))
+
+	for _, mode := range matchmaking.DefaultModes {
+		names = append(names, mode.Name)
+	}
+
+	return names
+}
+
+func validateEnqueueMatchmakingFields(requestBody enqueueMatchmakingRequestBody) (responseType, error) {
+	if errType, err := matchmakingModeValidator.Validate(requestBody.Mode); err != nil {
+		return matchmakingModeResponseErrors[errType], err
+	}
+
+	if errType, err := matchmakingRegionValidator.Validate(requestBody.Region); err != nil {
+		return matchmakingRegionResponseErrors[errType], err
+	}
+
+	return TYPE_UNKNOWN, nil
+}
+
+// newMatchmakingMatch creates the match of a group formed by the queue. It
+// is played with the default settings, on the default map, by as many
+// players as the mode groups. It is not listed in the lobby and only the
+// group can join it. It is deleted when nobody in the group joined it within
+// the join timeout.
+func newMatchmakingMatch(services matchServices, group matchmaking.Group) (string, error) {
+	settings := defaultCreateMatchRequestBody
+	settings.PlayersLimit = group.Mode.Players
+	settings.Teams = group.Mode.Teams
+
+	edges := game.EdgesWrap
+	if settings.Edges == "wall" {
+		edges = game.EdgesWall
+	}
+
+	selectedMap := game.Map{
+		Tiles: game.Tiles{
+			Horizontal: settings.MapWidth,
+			Vertical:   settings.MapHeight,
+		},
+		Edges: edges,
+	}
+
+	match, err := services.newMatch(group.Mode.Name, settings, selectedMap)
+	if err != nil {
+		return "", err
+	}
+
+	roster := make([]string, 0, len(group.Tickets))
+	for _, ticket := range group.Tickets {
+		roster = append(roster, ticket.AccountID)
+	}
+
+	match.UpdateState(game.MatchStateInput{
+		Roster: roster,
+	})
+
+	time.AfterFunc(services.env.Matchmaking.JoinTimeout, func() {
+		if len(match.GetPlayers()) == 0 {
+			services.matches.DeleteByID(match.GetID())
+		}
+	})
+
+	return match.GetID(), nil
+}
+
+// MatchmakingGroups creates the matches of the groups formed by the
+// matchmaking queue. It is meant to be given once to the queue. The queue
+// only tells the members of a group that its match failed, so why it
+// failed is logged here.
+func MatchmakingGroups(container container.Container) func(group matchmaking.Group) (string, error) {
+	services := retrieveMatchServices(container)
+
+	return func(group matchmaking.Group) (string, error) {
+		matchID, err := newMatchmakingMatch(services, group)
+		if err != nil {
+			accountIDs := make([]string, 0, len(group.Tickets))
+			for _, ticket := range group.Tickets {
+				accountIDs = append(accountIDs, ticket.AccountID)
+			}
+
+			ctx := withLogAttrs(
+				context.Background(),
+				slog.String("mode", group.Mode.Name),
+				slog.Any("account_ids", accountIDs),
+			)
+			handleError(ctx, err)
+		}
+
+		return matchID, err
+	}
+}
+
+// EnqueueMatchmaking puts the account in the matchmaking queue of a mode.
+// How it is doing is followed through MatchmakingStatus.
+func EnqueueMatchmaking(container container.Container) httprouter.Handle {
+	var (
+		queue             matchmaking.Queue
+		ratingsRepository db.RatingsRepository
+	)
+
+	err := container.Retrieve(&queue, &ratingsRepository)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		accountID := params.ByName("account_id")
+
+		var requestBody enqueueMatchmakingRequestBody
+
+		if err := json.NewDecoder(request.Body).Decode(&requestBody); err != nil && err != io.EOF {
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusUnprocessableEntity,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_PAYLOAD_INVALID,
+					Message: "payload is invalid",
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(request.Context(), err)
+			}
+
+			return
+		}
+
+		if responseType, err := validateEnqueueMatchmakingFields(requestBody); err != nil {
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusForbidden,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    responseType,
+					Message: err.Error(),
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(request.Context(), err)
+			}
+
+			return
+		}
+
+		ticket := matchmaking.Ticket{
+			AccountID: accountID,
+			Rating:    rating.Initial,
+			Region:    requestBody.Region,
+			Mode:      requestBody.Mode,
+		}
+
+		accountRating, err := ratingsRepository.GetByAccountID(request.Context(), accountID)
+		if err != nil {
+			handleError(request.Context(), err)
+
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusInternalServerError,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_UNKNOWN,
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(request.Context(), err)
+			}
+
+			return
+		}
+
+		if accountRating != nil {
+			ticket.Rating = accountRating.Rating
+		}
+
+		if err := queue.Enqueue(ticket); err != nil {
+			status := http.StatusInternalServerError
+			responseType := TYPE_UNKNOWN
+
+			if errors.Is(err, matchmaking.ErrAlreadyQueued) {
+				status = http.StatusConflict
+				responseType = TYPE_MATCHMAKING_ALREADY_QUEUED
+			} else {
+				handleError(request.Context(), err)
+			}
+
+			response := responseConfig{
+				Header: responseHeader{
+					Status: status,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    responseType,
+					Message: err.Error(),
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(request.Context(), err)
+			}
+
+			return
+		}
+
+		result := parseMatchmakingMessage(matchmaking.Status{
+			Status: matchmaking.StatusQueued,
+			Ticket: ticket,
+		}, time.Now())
+
+		if status, ok := queue.GetStatus(accountID); ok {
+			result = parseMatchmakingMessage(status, time.Now())
+		}
+
+		response := responseConfig{
+			Body: responseBody{
+				Success: true,
+				Result:  result.Matchmaking,
+			},
+		}
+
+		if err := makeResponse(request.Context(), writer, response); err != nil {
+			handleError(request.Context(), err)
+		}
+	}
+}
@@ -2,10 +2,70 @@ package routes
 
 import (
 	"context"
-	"log"
+	"errors"
+	"log/slog"
+
+	"github.com/Maycon-Santos/go-snake-backend/game"
+	"golang.org/x/exp/slices"
 )
 
+type logAttrsKey struct{}
+
+// withLogAttrs adds attributes, such as the match and player being served,
+// to every error handled with ctx.
+func withLogAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	return context.WithValue(ctx, logAttrsKey{}, append(logAttrs(ctx), attrs...))
+}
+
+func logAttrs(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(logAttrsKey{}).([]slog.Attr)
+
+	return attrs[:len(attrs):len(attrs)]
+}
+
 func handleError(ctx context.Context, err error) {
-	// WIP
-	log.Println(err)
+	attrs := slices.Clone(logAttrs(ctx))
+
+	var gameErr *game.Error
+	if errors.As(err, &gameErr) {
+		attrs = setLogAttr(attrs, "match_id", gameErr.MatchID)
+		attrs = setLogAttr(attrs, "player_id", gameErr.PlayerID)
+	}
+
+	slog.Default().LogAttrs(ctx, slog.LevelError, err.Error(), attrs...)
+}
+
+func setLogAttr(attrs []slog.Attr, key, value string) []slog.Attr {
+	if value == "" {
+		return attrs
+	}
+
+	for i, attr := range attrs {
+		if attr.Key == key {
+			attrs[i] = slog.String(key, value)
+			return attrs
+		}
+	}
+
+	return append(attrs, slog.String(key, value))
+}
+
+// logErrors handles the errors delivered by sink until done is closed,
+// including the ones still buffered by then.
+func logErrors(ctx context.Context, sink game.ErrorSink, done <-chan struct{}) {
+	for {
+		select {
+		case err := <-sink.Errors():
+			handleError(ctx, err)
+		case <-done:
+			for {
+				select {
+				case err := <-sink.Errors():
+					handleError(ctx, err)
+				default:
+					return
+				}
+			}
+		}
+	}
 }
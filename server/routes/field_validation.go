@@ -1,6 +1,9 @@
 package routes
 
-import "github.com/Maycon-Santos/go-snake-backend/validator"
+import (
+	"github.com/Maycon-Santos/go-snake-backend/game"
+	"github.com/Maycon-Santos/go-snake-backend/validator"
+)
 
 var usernameValidator = validator.
 	Field("username").
@@ -27,3 +30,227 @@ var passwordResponseErrors = map[string]responseType{
 	validator.MinLen:   TYPE_PASSWORD_BELOW_MIN_LEN,
 	validator.MaxLen:   TYPE_PASSWORD_ABOVE_MAX_LEN,
 }
+
+var playersLimitValidator = validator.
+	Field("players_limit").
+	Min(1).
+	Max(9)
+
+var playersLimitResponseErrors = map[string]responseType{
+	validator.Min: TYPE_PLAYERS_LIMIT_BELOW_MIN,
+	validator.Max: TYPE_PLAYERS_LIMIT_ABOVE_MAX,
+}
+
+var mapWidthValidator = validator.
+	Field("map_width").
+	Min(32).
+	Max(128)
+
+var mapWidthResponseErrors = map[string]responseType{
+	validator.Min: TYPE_MAP_WIDTH_BELOW_MIN,
+	validator.Max: TYPE_MAP_WIDTH_ABOVE_MAX,
+}
+
+var mapHeightValidator = validator.
+	Field("map_height").
+	Min(18).
+	Max(72)
+
+var mapHeightResponseErrors = map[string]responseType{
+	validator.Min: TYPE_MAP_HEIGHT_BELOW_MIN,
+	validator.Max: TYPE_MAP_HEIGHT_ABOVE_MAX,
+}
+
+var foodsLimitValidator = validator.
+	Field("foods_limit").
+	Min(1).
+	Max(16)
+
+var foodsLimitResponseErrors = map[string]responseType{
+	validator.Min: TYPE_FOODS_LIMIT_BELOW_MIN,
+	validator.Max: TYPE_FOODS_LIMIT_ABOVE_MAX,
+}
+
+var tickRateValidator = validator.
+	Field("tick_rate").
+	Min(5).
+	Max(30)
+
+var tickRateResponseErrors = map[string]responseType{
+	validator.Min: TYPE_TICK_RATE_BELOW_MIN,
+	validator.Max: TYPE_TICK_RATE_ABOVE_MAX,
+}
+
+var edgesValidator = validator.
+	Field("edges").
+	OneOf([]string{"wrap", "wall"})
+
+var edgesResponseErrors = map[string]responseType{
+	validator.OneOf: TYPE_EDGES_INVALID,
+}
+
+// The initial length is capped so a full match still finds room to spawn
+// every snake on the smallest map.
+var initialLengthValidator = validator.
+	Field("initial_length").
+	Min(2).
+	Max(8)
+
+var initialLengthResponseErrors = map[string]responseType{
+	validator.Min: TYPE_INITIAL_LENGTH_BELOW_MIN,
+	validator.Max: TYPE_INITIAL_LENGTH_ABOVE_MAX,
+}
+
+var timeLimitValidator = validator.
+	Field("time_limit").
+	Min(0).
+	Max(600)
+
+var timeLimitResponseErrors = map[string]responseType{
+	validator.Min: TYPE_TIME_LIMIT_BELOW_MIN,
+	validator.Max: TYPE_TIME_LIMIT_ABOVE_MAX,
+}
+
+var countdownValidator = validator.
+	Field("countdown").
+	Min(0).
+	Max(10)
+
+var countdownResponseErrors = map[string]responseType{
+	validator.Min: TYPE_COUNTDOWN_BELOW_MIN,
+	validator.Max: TYPE_COUNTDOWN_ABOVE_MAX,
+}
+
+var suddenDeathValidator = validator.
+	Field("sudden_death").
+	Min(0).
+	Max(120)
+
+var suddenDeathResponseErrors = map[string]responseType{
+	validator.Min: TYPE_SUDDEN_DEATH_BELOW_MIN,
+	validator.Max: TYPE_SUDDEN_DEATH_ABOVE_MAX,
+}
+
+var resultsValidator = validator.
+	Field("results").
+	Min(0).
+	Max(30)
+
+var resultsResponseErrors = map[string]responseType{
+	validator.Min: TYPE_RESULTS_BELOW_MIN,
+	validator.Max: TYPE_RESULTS_ABOVE_MAX,
+}
+
+// series is how many rounds the series of a match are the best of.
+var seriesValidator = validator.
+	Field("series").
+	Min(1).
+	Max(7)
+
+var seriesResponseErrors = map[string]responseType{
+	validator.Min: TYPE_SERIES_BELOW_MIN,
+	validator.Max: TYPE_SERIES_ABOVE_MAX,
+}
+
+// teams is how many teams the players are split in, zero for every player
+// on their own.
+var teamsValidator = validator.
+	Field("teams").
+	Min(0).
+	Max(game.MaxTeams)
+
+var teamsResponseErrors = map[string]responseType{
+	validator.Min: TYPE_TEAMS_BELOW_MIN,
+	validator.Max: TYPE_TEAMS_ABOVE_MAX,
+}
+
+// shrink is how many seconds apart the arena loses its outer ring, zero for
+// an arena that never shrinks.
+var shrinkValidator = validator.
+	Field("shrink").
+	Min(0).
+	Max(60)
+
+var shrinkResponseErrors = map[string]responseType{
+	validator.Min: TYPE_SHRINK_BELOW_MIN,
+	validator.Max: TYPE_SHRINK_ABOVE_MAX,
+}
+
+var shrinkWarningValidator = validator.
+	Field("shrink_warning").
+	Min(0).
+	Max(10)
+
+var shrinkWarningResponseErrors = map[string]responseType{
+	validator.Min: TYPE_SHRINK_WARNING_BELOW_MIN,
+	validator.Max: TYPE_SHRINK_WARNING_ABOVE_MAX,
+}
+
+// min_arena is the side of the smallest square the arena shrinks to.
+var minArenaValidator = validator.
+	Field("min_arena").
+	Min(4).
+	Max(64)
+
+var minArenaResponseErrors = map[string]responseType{
+	validator.Min: TYPE_MIN_ARENA_BELOW_MIN,
+	validator.Max: TYPE_MIN_ARENA_ABOVE_MAX,
+}
+
+// items is how many power-ups can lie on the map at once, zero for a match
+// without any.
+var itemsValidator = validator.
+	Field("items").
+	Min(0).
+	Max(5)
+
+var itemsResponseErrors = map[string]responseType{
+	validator.Min: TYPE_ITEMS_BELOW_MIN,
+	validator.Max: TYPE_ITEMS_ABOVE_MAX,
+}
+
+var headOnValidator = validator.
+	Field("head_on").
+	OneOf([]string{"both_die", "longer_wins"})
+
+var headOnResponseErrors = map[string]responseType{
+	validator.OneOf: TYPE_HEAD_ON_INVALID,
+}
+
+var mapNameValidator = validator.
+	Field("map").
+	OneOf(game.MapNames())
+
+var mapNameResponseErrors = map[string]responseType{
+	validator.OneOf: TYPE_MAP_NOT_FOUND,
+}
+
+var mapTitleValidator = validator.
+	Field("name").
+	Required().
+	MinLen(3).
+	MaxLen(30)
+
+var mapTitleResponseErrors = map[string]responseType{
+	validator.Required: TYPE_MAP_NAME_MISSING,
+	validator.MinLen:   TYPE_MAP_NAME_BELOW_MIN_LEN,
+	validator.MaxLen:   TYPE_MAP_NAME_ABOVE_MAX_LEN,
+}
+
+var matchmakingModeValidator = validator.
+	Field("mode").
+	Required().
+	OneOf(matchmakingModeNames())
+
+var matchmakingModeResponseErrors = map[string]responseType{
+	validator.Required: TYPE_MATCHMAKING_MODE_INVALID,
+	validator.OneOf:    TYPE_MATCHMAKING_MODE_INVALID,
+}
+
+var matchmakingRegionValidator = validator.
+	Field("region").
+	MaxLen(16)
+
+var matchmakingRegionResponseErrors = map[string]responseType{
+	validator.MaxLen: TYPE_MATCHMAKING_REGION_ABOVE_MAX_LEN,
+}
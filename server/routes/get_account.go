@@ -2,10 +2,12 @@ package routes
 
 import (
 	"log"
+	"math"
 	"net/http"
 
 	"github.com/Maycon-Santos/go-snake-backend/container"
 	"github.com/Maycon-Santos/go-snake-backend/db"
+	"github.com/Maycon-Santos/go-snake-backend/rating"
 	"github.com/julienschmidt/httprouter"
 )
 
@@ -15,18 +17,21 @@ type skinResult struct {
 }
 
 type getUserResponseResult struct {
-	ID       string     `json:"id"`
-	Username string     `json:"username"`
-	Skin     skinResult `json:"skin"`
+	ID           string     `json:"id"`
+	Username     string     `json:"username"`
+	Skin         skinResult `json:"skin"`
+	Rating       int        `json:"rating"`
+	RatedMatches int        `json:"rated_matches"`
 }
 
 func GetAccount(container container.Container) httprouter.Handle {
 	var (
 		accountsRepository db.AccountsRepository
 		skinsRepository    db.SkinsRepository
+		ratingsRepository  db.RatingsRepository
 	)
 
-	err := container.Retrieve(&accountsRepository, &skinsRepository)
+	err := container.Retrieve(&accountsRepository, &skinsRepository, &ratingsRepository)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -38,6 +43,7 @@ func GetAccount(container container.Container) httprouter.Handle {
 		result := getUserResponseResult{
 			ID:       accountID,
 			Username: username,
+			Rating:   int(math.Round(rating.Initial)),
 		}
 
 		skin, err := skinsRepository.GetAccountSkin(request.Context(), accountID)
@@ -53,6 +59,17 @@ func GetAccount(container container.Container) httprouter.Handle {
 			}
 		}
 
+		accountRating, err := ratingsRepository.GetByAccountID(request.Context(), accountID)
+		if err != nil {
+			handleError(request.Context(), err)
+			return
+		}
+
+		if accountRating != nil {
+			result.Rating = int(math.Round(accountRating.Rating))
+			result.RatedMatches = accountRating.Matches
+		}
+
 		response := responseConfig{
 			Body: responseBody{
 				Success: true,
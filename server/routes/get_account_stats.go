@@ -0,0 +1,124 @@
+package routes
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/Maycon-Santos/go-snake-backend/container"
+	"github.com/Maycon-Santos/go-snake-backend/db"
+	"github.com/julienschmidt/httprouter"
+)
+
+type getAccountStatsResponseResult struct {
+	AccountID        string  `json:"account_id"`
+	Matches          int     `json:"matches"`
+	Wins             int     `json:"wins"`
+	WinRate          float64 `json:"win_rate"`
+	BestLength       int     `json:"best_length"`
+	Kills            int     `json:"kills"`
+	FoodEaten        int     `json:"food_eaten"`
+	AveragePlacement float64 `json:"average_placement"`
+}
+
+// GetAccountStats sums up the rounds an account played.
+func GetAccountStats(container container.Container) httprouter.Handle {
+	var (
+		accountsRepository db.AccountsRepository
+		matchesRepository  db.MatchesRepository
+	)
+
+	err := container.Retrieve(&accountsRepository, &matchesRepository)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		accountID := params.ByName("id")
+
+		account, err := accountsRepository.GetByID(request.Context(), accountID)
+		if err != nil {
+			handleError(request.Context(), err)
+
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusInternalServerError,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_UNKNOWN,
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(request.Context(), err)
+			}
+
+			return
+		}
+
+		if account == nil {
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusNotFound,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_ACCOUNT_NOT_FOUND,
+					Message: "account not found",
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(request.Context(), err)
+			}
+
+			return
+		}
+
+		stats, err := matchesRepository.GetAccountStats(request.Context(), accountID)
+		if err != nil {
+			handleError(request.Context(), err)
+
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusInternalServerError,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_UNKNOWN,
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(request.Context(), err)
+			}
+
+			return
+		}
+
+		result := getAccountStatsResponseResult{
+			AccountID:        account.ID,
+			Matches:          stats.Matches,
+			Wins:             stats.Wins,
+			BestLength:       stats.BestLength,
+			Kills:            stats.Kills,
+			FoodEaten:        stats.FoodEaten,
+			AveragePlacement: stats.AveragePlacement,
+		}
+
+		if stats.Matches > 0 {
+			result.WinRate = float64(stats.Wins) / float64(stats.Matches)
+		}
+
+		response := responseConfig{
+			Body: responseBody{
+				Success: true,
+				Result:  result,
+			},
+		}
+
+		if err := makeResponse(request.Context(), writer, response); err != nil {
+			handleError(request.Context(), err)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package routes
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Maycon-Santos/go-snake-backend/container"
+	"github.com/Maycon-Santos/go-snake-backend/db"
+	"github.com/Maycon-Santos/go-snake-backend/leaderboard"
+	"github.com/julienschmidt/httprouter"
+)
+
+var errLeaderboardSeasonInvalid = errors.New("season must be \"all\" or written as 2006-01")
+
+type leaderboardQuery struct {
+	Board    leaderboard.Board
+	Season   string
+	AroundMe bool
+	Limit    int
+	Offset   int
+}
+
+type leaderboardEntryResult struct {
+	Rank      int64  `json:"rank"`
+	AccountID string `json:"account_id"`
+	Username  string `json:"username"`
+	Score     int64  `json:"score"`
+}
+
+type getLeaderboardResponseResult struct {
+	Board   string                   `json:"board"`
+	Season  string                   `json:"season"`
+	Entries []leaderboardEntryResult `json:"entries"`
+	Limit   int                      `json:"limit"`
+	Offset  int                      `json:"offset"`
+}
+
+// parseLeaderboardQuery reads ?board= (wins by default), ?season= (the
+// current one by default, or "all") and ?around=me along with the
+// pagination.
+func parseLeaderboardQuery(query url.Values, now time.Time) (leaderboardQuery, responseType, error) {
+	parsed := leaderboardQuery{
+		Board:    leaderboard.BoardWins,
+		Season:   leaderboard.Season(now),
+		AroundMe: query.Get("around") == "me",
+	}
+
+	parsed.Limit, parsed.Offset = parsePagination(query)
+
+	if name := query.Get("board"); name != "" {
+		board, err := leaderboard.ParseBoard(name)
+		if err != nil {
+			return parsed, TYPE_LEADERBOARD_BOARD_INVALID, err
+		}
+
+		parsed.Board = board
+	}
+
+	if season := query.Get("season"); season != "" {
+		if season != leaderboard.AllTime {
+			if _, err := time.Parse("2006-01", season); err != nil {
+				return parsed, TYPE_LEADERBOARD_SEASON_INVALID, errLeaderboardSeasonInvalid
+			}
+		}
+
+		parsed.Season = season
+	}
+
+	return parsed, "", nil
+}
+
+// GetLeaderboard lists a leaderboard from the top, paginated with ?limit=
+// and ?offset=, or, with ?around=me, around the account asking for it.
+func GetLeaderboard(container container.Container) httprouter.Handle {
+	var (
+		leaderboards       leaderboard.Leaderboards
+		accountsRepository db.AccountsRepository
+	)
+
+	err := container.Retrieve(&leaderboards, &accountsRepository)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		query, responseType, err := parseLeaderboardQuery(request.URL.Query(), time.Now())
+		if err != nil {
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusBadRequest,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    responseType,
+					Message: err.Error(),
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(request.Context(), err)
+			}
+
+			return
+		}
+
+		var entries []leaderboard.Entry
+
+		if query.AroundMe {
+			entries, err = leaderboards.Around(request.Context(), query.Board, query.Season, params.ByName("account_id"), query.Limit/2)
+		} else {
+			entries, err = leaderboards.Top(request.Context(), query.Board, query.Season, query.Limit, query.Offset)
+		}
+
+		result := getLeaderboardResponseResult{
+			Board:   string(query.Board),
+			Season:  query.Season,
+			Entries: make([]leaderboardEntryResult, 0, len(entries)),
+			Limit:   query.Limit,
+			Offset:  query.Offset,
+		}
+
+		if err == nil {
+			for _, entry := range entries {
+				var account *db.Account
+
+				account, err = accountsRepository.GetByID(request.Context(), entry.AccountID)
+				if err != nil {
+					break
+				}
+
+				entryResult := leaderboardEntryResult{
+					Rank:      entry.Rank,
+					AccountID: entry.AccountID,
+					Score:     int64(entry.Score),
+				}
+
+				if account != nil {
+					entryResult.Username = account.UserName
+				}
+
+				result.Entries = append(result.Entries, entryResult)
+			}
+		}
+
+		if err != nil {
+			handleError(request.Context(), err)
+
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusInternalServerError,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_UNKNOWN,
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(request.Context(), err)
+			}
+
+			return
+		}
+
+		response := responseConfig{
+			Body: responseBody{
+				Success: true,
+				Result:  result,
+			},
+		}
+
+		if err := makeResponse(request.Context(), writer, response); err != nil {
+			handleError(request.Context(), err)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package routes
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Maycon-Santos/go-snake-backend/leaderboard"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLeaderboardQuery(t *testing.T) {
+	now := time.Date(2023, 11, 12, 19, 0, 0, 0, time.UTC)
+
+	t.Run("should default to the wins of the current season", func(t *testing.T) {
+		query, _, err := parseLeaderboardQuery(url.Values{}, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, leaderboard.BoardWins, query.Board)
+		assert.Equal(t, "2023-11", query.Season)
+		assert.False(t, query.AroundMe)
+		assert.Equal(t, defaultPageSize, query.Limit)
+	})
+
+	t.Run("should read the board, season and around", func(t *testing.T) {
+		query, _, err := parseLeaderboardQuery(url.Values{
+			"board":  {"kills"},
+			"season": {"all"},
+			"around": {"me"},
+		}, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, leaderboard.BoardKills, query.Board)
+		assert.Equal(t, leaderboard.AllTime, query.Season)
+		assert.True(t, query.AroundMe)
+	})
+
+	t.Run("should response an error of unknown board", func(t *testing.T) {
+		_, responseType, err := parseLeaderboardQuery(url.Values{"board": {"deaths"}}, now)
+
+		assert.Error(t, err)
+		assert.Equal(t, TYPE_LEADERBOARD_BOARD_INVALID, responseType)
+	})
+
+	t.Run("should response an error of invalid season", func(t *testing.T) {
+		_, responseType, err := parseLeaderboardQuery(url.Values{"season": {"2023-13"}}, now)
+
+		assert.Error(t, err)
+		assert.Equal(t, TYPE_LEADERBOARD_SEASON_INVALID, responseType)
+	})
+}
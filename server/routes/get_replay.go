@@ -0,0 +1,115 @@
+package routes
+
+import (
+	"encoding/json"
+	"log"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Maycon-Santos/go-snake-backend/container"
+	"github.com/Maycon-Santos/go-snake-backend/db"
+	"github.com/julienschmidt/httprouter"
+)
+
+// getReplayResponseResult is the replay of the last round of a match, along
+// with the replays of all of its rounds, so the earlier ones of a series can
+// be watched by their ID too.
+type getReplayResponseResult struct {
+	ID        string              `json:"id"`
+	MatchID   string              `json:"match_id"`
+	CreatedAt time.Time           `json:"created_at"`
+	Log       json.RawMessage     `json:"log"`
+	Rounds    []replayRoundResult `json:"rounds"`
+}
+
+type replayRoundResult struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func GetReplay(container container.Container) httprouter.Handle {
+	var replaysRepository db.ReplaysRepository
+
+	err := container.Retrieve(&replaysRepository)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		matchID := params.ByName("match_id")
+		ctx := withLogAttrs(request.Context(), slog.String("match_id", matchID))
+
+		replay, err := replaysRepository.GetLastByMatchID(request.Context(), matchID)
+
+		var rounds []db.Replay
+		if err == nil && replay != nil {
+			rounds, err = replaysRepository.ListByMatchID(request.Context(), matchID)
+		}
+
+		if err != nil {
+			handleError(ctx, err)
+
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusInternalServerError,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_UNKNOWN,
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(ctx, err)
+			}
+
+			return
+		}
+
+		if replay == nil {
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusNotFound,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_REPLAY_NOT_FOUND,
+					Message: "replay not found",
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(ctx, err)
+			}
+
+			return
+		}
+
+		result := getReplayResponseResult{
+			ID:        replay.ID,
+			MatchID:   replay.MatchID,
+			CreatedAt: replay.CreatedAt,
+			Log:       replay.Log,
+			Rounds:    make([]replayRoundResult, 0, len(rounds)),
+		}
+
+		for _, round := range rounds {
+			result.Rounds = append(result.Rounds, replayRoundResult{
+				ID:        round.ID,
+				CreatedAt: round.CreatedAt,
+			})
+		}
+
+		response := responseConfig{
+			Body: responseBody{
+				Success: true,
+				Result:  result,
+			},
+		}
+
+		if err := makeResponse(request.Context(), writer, response); err != nil {
+			handleError(ctx, err)
+		}
+	}
+}
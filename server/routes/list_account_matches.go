@@ -0,0 +1,117 @@
+package routes
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/Maycon-Santos/go-snake-backend/container"
+	"github.com/Maycon-Santos/go-snake-backend/db"
+	"github.com/julienschmidt/httprouter"
+)
+
+type listAccountMatchesResponseResult struct {
+	Matches []matchHistoryResult `json:"matches"`
+	Limit   int                  `json:"limit"`
+	Offset  int                  `json:"offset"`
+}
+
+// ListAccountMatches lists the rounds an account played, the latest first.
+// It is paginated with ?limit= and ?offset=.
+func ListAccountMatches(container container.Container) httprouter.Handle {
+	var (
+		accountsRepository db.AccountsRepository
+		matchesRepository  db.MatchesRepository
+	)
+
+	err := container.Retrieve(&accountsRepository, &matchesRepository)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		accountID := params.ByName("id")
+		limit, offset := parsePagination(request.URL.Query())
+
+		account, err := accountsRepository.GetByID(request.Context(), accountID)
+		if err != nil {
+			handleError(request.Context(), err)
+
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusInternalServerError,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_UNKNOWN,
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(request.Context(), err)
+			}
+
+			return
+		}
+
+		if account == nil {
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusNotFound,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_ACCOUNT_NOT_FOUND,
+					Message: "account not found",
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(request.Context(), err)
+			}
+
+			return
+		}
+
+		matches, err := matchesRepository.ListByAccountID(request.Context(), accountID, limit, offset)
+		if err != nil {
+			handleError(request.Context(), err)
+
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusInternalServerError,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_UNKNOWN,
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(request.Context(), err)
+			}
+
+			return
+		}
+
+		result := listAccountMatchesResponseResult{
+			Matches: make([]matchHistoryResult, 0, len(matches)),
+			Limit:   limit,
+			Offset:  offset,
+		}
+
+		for _, match := range matches {
+			result.Matches = append(result.Matches, parseMatchHistoryResult(match))
+		}
+
+		response := responseConfig{
+			Body: responseBody{
+				Success: true,
+				Result:  result,
+			},
+		}
+
+		if err := makeResponse(request.Context(), writer, response); err != nil {
+			handleError(request.Context(), err)
+		}
+	}
+}
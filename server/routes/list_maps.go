@@ -0,0 +1,84 @@
+package routes
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/Maycon-Santos/go-snake-backend/container"
+	"github.com/Maycon-Santos/go-snake-backend/db"
+	"github.com/julienschmidt/httprouter"
+)
+
+type listMapsResponseResult struct {
+	Maps   []mapResponseResult `json:"maps"`
+	Limit  int                 `json:"limit"`
+	Offset int                 `json:"offset"`
+}
+
+// ListMaps lists the published maps along with the drafts of the account,
+// or only the maps of the account with ?mine=true. It is paginated with
+// ?limit= and ?offset=.
+func ListMaps(container container.Container) httprouter.Handle {
+	var mapsRepository db.MapsRepository
+
+	err := container.Retrieve(&mapsRepository)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		accountID := params.ByName("account_id")
+		query := request.URL.Query()
+
+		onlyOwned := query.Get("mine") == "true"
+		limit, offset := parsePagination(query)
+
+		storedMaps, err := mapsRepository.List(request.Context(), accountID, onlyOwned, limit, offset)
+		if err != nil {
+			handleError(request.Context(), err)
+
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusInternalServerError,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_UNKNOWN,
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(request.Context(), err)
+			}
+
+			return
+		}
+
+		result := listMapsResponseResult{
+			Maps:   make([]mapResponseResult, 0, len(storedMaps)),
+			Limit:  limit,
+			Offset: offset,
+		}
+
+		for _, storedMap := range storedMaps {
+			mapResult, err := parseMapResponseResult(storedMap)
+			if err != nil {
+				handleError(request.Context(), err)
+				continue
+			}
+
+			result.Maps = append(result.Maps, mapResult)
+		}
+
+		response := responseConfig{
+			Body: responseBody{
+				Success: true,
+				Result:  result,
+			},
+		}
+
+		if err := makeResponse(request.Context(), writer, response); err != nil {
+			handleError(request.Context(), err)
+		}
+	}
+}
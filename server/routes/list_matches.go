@@ -0,0 +1,92 @@
+package routes
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Maycon-Santos/go-snake-backend/container"
+	"github.com/Maycon-Santos/go-snake-backend/game"
+	"github.com/Maycon-Santos/go-snake-backend/utils"
+	"github.com/julienschmidt/httprouter"
+)
+
+type matchListingResult struct {
+	ID           string `json:"id"`
+	Owner        string `json:"owner"`
+	Players      int    `json:"players"`
+	PlayersLimit int    `json:"players_limit"`
+	Status       string `json:"status"`
+	Map          string `json:"map"`
+	MapWidth     int    `json:"map_width"`
+	MapHeight    int    `json:"map_height"`
+	Mode         string `json:"mode"`
+}
+
+type listMatchesResponseResult struct {
+	Matches []matchListingResult `json:"matches"`
+	Limit   int                  `json:"limit"`
+	Offset  int                  `json:"offset"`
+}
+
+// parseMatchFilter reads the filters of the lobby: ?mode=, ?status= (lobby,
+// countdown, running, sudden_death or results) and ?open=false to list the
+// full matches too. Only public matches are ever listed.
+func parseMatchFilter(query url.Values) game.MatchFilter {
+	filter := game.MatchFilter{
+		Public: utils.Ptr(true),
+		Mode:   query.Get("mode"),
+		Open:   query.Get("open") != "false",
+	}
+
+	status := strings.ToUpper(query.Get("status"))
+
+	for _, s := range game.Statuses {
+		if string(s) == status && s != game.StatusClosed {
+			filter.Status = utils.Ptr(s)
+		}
+	}
+
+	return filter
+}
+
+// ListMatches lists the public matches for a server browser. It is
+// paginated with ?limit= and ?offset=; LobbySocket keeps the list up to
+// date.
+func ListMatches(container container.Container) httprouter.Handle {
+	var matches game.Matches
+
+	err := container.Retrieve(&matches)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		query := request.URL.Query()
+		limit, offset := parsePagination(query)
+
+		listed := matches.List(parseMatchFilter(query))
+
+		result := listMatchesResponseResult{
+			Matches: make([]matchListingResult, 0, limit),
+			Limit:   limit,
+			Offset:  offset,
+		}
+
+		for i := offset; i < len(listed) && i < offset+limit; i++ {
+			result.Matches = append(result.Matches, matchListingResult(newLobbyMatchMessage(listed[i])))
+		}
+
+		response := responseConfig{
+			Body: responseBody{
+				Success: true,
+				Result:  result,
+			},
+		}
+
+		if err := makeResponse(request.Context(), writer, response); err != nil {
+			handleError(request.Context(), err)
+		}
+	}
+}
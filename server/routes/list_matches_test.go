@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/Maycon-Santos/go-snake-backend/game"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMatchFilter(t *testing.T) {
+	t.Run("should list only the public matches with room by default", func(t *testing.T) {
+		filter := parseMatchFilter(url.Values{})
+
+		assert.True(t, *filter.Public)
+		assert.True(t, filter.Open)
+		assert.Nil(t, filter.Status)
+		assert.Empty(t, filter.Mode)
+	})
+
+	t.Run("should read the mode, status and open filters", func(t *testing.T) {
+		filter := parseMatchFilter(url.Values{
+			"mode":   {"duel"},
+			"status": {"running"},
+			"open":   {"false"},
+		})
+
+		assert.True(t, *filter.Public)
+		assert.False(t, filter.Open)
+		assert.Equal(t, game.StatusRunning, *filter.Status)
+		assert.Equal(t, "duel", filter.Mode)
+	})
+
+	t.Run("should ignore an unknown or closed status", func(t *testing.T) {
+		assert.Nil(t, parseMatchFilter(url.Values{"status": {"on_hold"}}).Status)
+		assert.Nil(t, parseMatchFilter(url.Values{"status": {"closed"}}).Status)
+		assert.Equal(t, game.StatusSuddenDeath, *parseMatchFilter(url.Values{"status": {"sudden_death"}}).Status)
+	})
+}
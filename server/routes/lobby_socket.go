@@ -0,0 +1,186 @@
+package routes
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/Maycon-Santos/go-snake-backend/container"
+	"github.com/Maycon-Santos/go-snake-backend/game"
+	"github.com/julienschmidt/httprouter"
+)
+
+const lobbyQueueSize = 64
+
+// lobby follows the public matches and fans their changes out to the
+// sockets watching the lobby. A socket too slow to keep up is dropped.
+type lobby struct {
+	matches     game.Matches
+	listed      map[string]lobbyMatchMessage
+	subscribers map[chan message]struct{}
+	sync        sync.Mutex
+}
+
+func newLobby(matches game.Matches) *lobby {
+	l := &lobby{
+		matches:     matches,
+		listed:      make(map[string]lobbyMatchMessage),
+		subscribers: make(map[chan message]struct{}),
+	}
+
+	matches.OnChange(l.publish)
+
+	return l
+}
+
+// publish turns a change of the matches into a change of the lobby. A match
+// is added once it is public, updated when what is shown of it changes and
+// removed when it is gone or no longer public.
+func (l *lobby) publish(event game.MatchesEvent) {
+	id := event.Match.GetID()
+
+	listed := event.Type != game.MatchRemoved && event.Match.IsPublic()
+	if listed {
+		_, err := l.matches.GetMatchByID(id)
+		listed = err == nil
+	}
+
+	var current lobbyMatchMessage
+	if listed {
+		current = newLobbyMatchMessage(event.Match)
+	}
+
+	l.sync.Lock()
+	defer l.sync.Unlock()
+
+	previous, wasListed := l.listed[id]
+
+	var msg message
+
+	switch {
+	case listed && !wasListed:
+		msg = parseLobbyMessage(string(game.MatchAdded), current)
+		l.listed[id] = current
+	case listed && previous != current:
+		msg = parseLobbyMessage(string(game.MatchUpdated), current)
+		l.listed[id] = current
+	case !listed && wasListed:
+		msg = parseLobbyMessage(string(game.MatchRemoved), previous)
+		delete(l.listed, id)
+	default:
+		return
+	}
+
+	for subscriber := range l.subscribers {
+		select {
+		case subscriber <- msg:
+		default:
+			delete(l.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+// subscribe receives the changes of the lobby, starting with every match
+// listed in it. The channel is closed when the subscriber falls behind.
+func (l *lobby) subscribe() (<-chan message, func()) {
+	l.sync.Lock()
+	defer l.sync.Unlock()
+
+	listed := make([]lobbyMatchMessage, 0, len(l.listed))
+	for _, match := range l.listed {
+		listed = append(listed, match)
+	}
+
+	sort.Slice(listed, func(a, b int) bool {
+		return listed[a].ID < listed[b].ID
+	})
+
+	ch := make(chan message, len(listed)+lobbyQueueSize)
+
+	for _, match := range listed {
+		ch <- parseLobbyMessage(string(game.MatchAdded), match)
+	}
+
+	l.subscribers[ch] = struct{}{}
+
+	unsubscribe := func() {
+		l.sync.Lock()
+		defer l.sync.Unlock()
+
+		if _, ok := l.subscribers[ch]; ok {
+			delete(l.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	return ch, unsubscribe
+}
+
+// LobbySocket streams the public matches over a socket: every match listed
+// when it connects, then each match added, updated or removed.
+func LobbySocket(container container.Container) httprouter.Handle {
+	var matches game.Matches
+
+	err := container.Retrieve(&matches)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	l := newLobby(matches)
+
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		// The socket outlives the request, so errors are handled with a
+		// context that is not canceled when the handler returns.
+		ctx := context.WithoutCancel(request.Context())
+
+		socket, err := upgradeSocket(writer, request)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+
+		defer socket.Close()
+
+		codec := socketCodec(socket)
+
+		closed := make(chan struct{})
+
+		go func() {
+			defer close(closed)
+
+			for {
+				if _, _, err := socket.NextReader(); err != nil {
+					return
+				}
+			}
+		}()
+
+		messages, unsubscribe := l.subscribe()
+		defer unsubscribe()
+
+		for {
+			select {
+			case <-closed:
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				msgBytes, err := codec.Encode(msg)
+				if err != nil {
+					handleError(ctx, err)
+					return
+				}
+
+				if err = socket.WriteMessage(codec.MessageType(), msgBytes); err != nil {
+					handleError(ctx, err)
+					return
+				}
+			}
+		}
+	}
+}
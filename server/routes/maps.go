@@ -0,0 +1,172 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Maycon-Santos/go-snake-backend/db"
+	"github.com/Maycon-Santos/go-snake-backend/game"
+)
+
+// mapRequestBody is a map drawn in the map editor. Grid has one string per
+// row, where '#' is a wall and '.' is a free tile.
+type mapRequestBody struct {
+	Name         string   `json:"name"`
+	PlayersLimit int      `json:"players_limit"`
+	Edges        string   `json:"edges"`
+	Grid         []string `json:"grid"`
+}
+
+type mapResponseResult struct {
+	ID           string    `json:"id"`
+	OwnerID      string    `json:"owner_id"`
+	Name         string    `json:"name"`
+	PlayersLimit int       `json:"players_limit"`
+	Published    bool      `json:"published"`
+	Edges        string    `json:"edges"`
+	Grid         []string  `json:"grid"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+var errMapPublished = errors.New("the map is published and can no longer be changed")
+
+var mapValidationResponseErrors = map[error]responseType{
+	game.ErrSpawnBlocked:     TYPE_MAP_SPAWN_BLOCKED,
+	game.ErrSpawnUnreachable: TYPE_MAP_SPAWN_UNREACHABLE,
+	game.ErrNoRoomForFood:    TYPE_MAP_NO_FOOD_SPACE,
+}
+
+// parseMapRequestBody validates the fields of a map drawn in the map editor
+// and builds it. The spawn points are checked for snakes of the default
+// initial length; a match asking for longer snakes checks them again.
+func parseMapRequestBody(requestBody mapRequestBody) (game.Map, responseType, error) {
+	if errType, err := mapTitleValidator.Validate(requestBody.Name); err != nil {
+		return game.Map{}, mapTitleResponseErrors[errType], err
+	}
+
+	if errType, err := playersLimitValidator.Validate(requestBody.PlayersLimit); err != nil {
+		return game.Map{}, playersLimitResponseErrors[errType], err
+	}
+
+	if errType, err := edgesValidator.Validate(requestBody.Edges); err != nil {
+		return game.Map{}, edgesResponseErrors[errType], err
+	}
+
+	if errType, err := mapHeightValidator.Validate(len(requestBody.Grid)); err != nil {
+		return game.Map{}, mapHeightResponseErrors[errType], err
+	}
+
+	if errType, err := mapWidthValidator.Validate(len(requestBody.Grid[0])); err != nil {
+		return game.Map{}, mapWidthResponseErrors[errType], err
+	}
+
+	edges := game.EdgesWrap
+	if requestBody.Edges == "wall" {
+		edges = game.EdgesWall
+	}
+
+	_map, err := game.NewMapFromGrid(edges, requestBody.Grid)
+	if err != nil {
+		return game.Map{}, TYPE_MAP_INVALID, err
+	}
+
+	_map.Name = requestBody.Name
+
+	if responseType, err := validateMapLayout(_map, requestBody.PlayersLimit, defaultCreateMatchRequestBody.InitialLength); err != nil {
+		return game.Map{}, responseType, err
+	}
+
+	return _map, TYPE_UNKNOWN, nil
+}
+
+func validateMapLayout(_map game.Map, playersLimit, initialLength int) (responseType, error) {
+	err := game.ValidateMap(_map, playersLimit, initialLength)
+	if err == nil {
+		return TYPE_UNKNOWN, nil
+	}
+
+	for target, responseType := range mapValidationResponseErrors {
+		if errors.Is(err, target) {
+			return responseType, err
+		}
+	}
+
+	return TYPE_MAP_INVALID, err
+}
+
+// saveMap stores a new map, or replaces the draft mapID when it is set, and
+// reads it back. Drafts that were published meanwhile are left as they are
+// and errMapPublished is returned.
+func saveMap(ctx context.Context, mapsRepository db.MapsRepository, mapID, ownerID string, playersLimit int, _map game.Map) (*db.Map, error) {
+	definition, err := json.Marshal(_map)
+	if err != nil {
+		return nil, err
+	}
+
+	if mapID == "" {
+		mapID, err = mapsRepository.Save(ctx, ownerID, _map.Name, playersLimit, definition)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		updated, err := mapsRepository.Update(ctx, mapID, ownerID, _map.Name, playersLimit, definition)
+		if err != nil {
+			return nil, err
+		}
+
+		if !updated {
+			return nil, errMapPublished
+		}
+	}
+
+	storedMap, err := mapsRepository.GetByID(ctx, mapID)
+	if err != nil {
+		return nil, err
+	}
+
+	if storedMap == nil {
+		return nil, fmt.Errorf("map %s is gone", mapID)
+	}
+
+	return storedMap, nil
+}
+
+func decodeMapDefinition(storedMap db.Map) (game.Map, error) {
+	var _map game.Map
+
+	if err := json.Unmarshal(storedMap.Definition, &_map); err != nil {
+		return game.Map{}, err
+	}
+
+	return _map, nil
+}
+
+func parseMapResponseResult(storedMap db.Map) (mapResponseResult, error) {
+	_map, err := decodeMapDefinition(storedMap)
+	if err != nil {
+		return mapResponseResult{}, err
+	}
+
+	return mapResponseResult{
+		ID:           storedMap.ID,
+		OwnerID:      storedMap.OwnerID,
+		Name:         storedMap.Name,
+		PlayersLimit: storedMap.PlayersLimit,
+		Published:    storedMap.Published,
+		Edges:        strings.ToLower(string(_map.Edges)),
+		Grid:         _map.Grid(),
+		CreatedAt:    storedMap.CreatedAt,
+		UpdatedAt:    storedMap.UpdatedAt,
+	}, nil
+}
+
+// canUseMap tells whether accountID may see or play storedMap: drafts are
+// only available to their owner.
+func canUseMap(storedMap *db.Map, accountID string) bool {
+	return storedMap != nil && (storedMap.Published || storedMap.OwnerID == accountID)
+}
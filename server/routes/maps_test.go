@@ -0,0 +1,77 @@
+package routes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMapRequestBody(t *testing.T) {
+	newRequestBody := func() mapRequestBody {
+		grid := make([]string, 18)
+		for y := range grid {
+			grid[y] = strings.Repeat(".", 32)
+		}
+
+		return mapRequestBody{
+			Name:         "open field",
+			PlayersLimit: 4,
+			Edges:        "wall",
+			Grid:         grid,
+		}
+	}
+
+	t.Run("should build the map drawn in the grid", func(t *testing.T) {
+		requestBody := newRequestBody()
+		requestBody.Grid[0] = "#" + requestBody.Grid[0][1:]
+
+		_map, _, err := parseMapRequestBody(requestBody)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "open field", _map.Name)
+		assert.Equal(t, requestBody.Grid, _map.Grid())
+	})
+
+	t.Run("should response an error when `name` field is missing", func(t *testing.T) {
+		requestBody := newRequestBody()
+		requestBody.Name = ""
+
+		_, responseType, err := parseMapRequestBody(requestBody)
+
+		assert.EqualError(t, err, "missing name field")
+		assert.Equal(t, TYPE_MAP_NAME_MISSING, responseType)
+	})
+
+	t.Run("should response an error when the grid is too small", func(t *testing.T) {
+		requestBody := newRequestBody()
+		requestBody.Grid = requestBody.Grid[:10]
+
+		_, responseType, err := parseMapRequestBody(requestBody)
+
+		assert.Error(t, err)
+		assert.Equal(t, TYPE_MAP_HEIGHT_BELOW_MIN, responseType)
+	})
+
+	t.Run("should response an error when the grid has an unknown tile", func(t *testing.T) {
+		requestBody := newRequestBody()
+		requestBody.Grid[5] = "x" + requestBody.Grid[5][1:]
+
+		_, responseType, err := parseMapRequestBody(requestBody)
+
+		assert.Error(t, err)
+		assert.Equal(t, TYPE_MAP_INVALID, responseType)
+	})
+
+	t.Run("should response an error when there is no room to spawn every snake", func(t *testing.T) {
+		requestBody := newRequestBody()
+		for y := range requestBody.Grid {
+			requestBody.Grid[y] = strings.Repeat("#", 4) + "." + strings.Repeat("#", 27)
+		}
+
+		_, responseType, err := parseMapRequestBody(requestBody)
+
+		assert.Error(t, err)
+		assert.Equal(t, TYPE_MAP_SPAWN_BLOCKED, responseType)
+	})
+}
@@ -0,0 +1,158 @@
+package routes
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Maycon-Santos/go-snake-backend/db"
+	"github.com/Maycon-Santos/go-snake-backend/game"
+)
+
+// matchHistorySettings is how the settings of a round are kept in the match
+// history. Teams tells the team of every player when the round was played
+// in teams.
+type matchHistorySettings struct {
+	Mode          string              `json:"mode"`
+	PlayersLimit  int                 `json:"players_limit"`
+	FoodsLimit    int                 `json:"foods_limit"`
+	InitialLength int                 `json:"initial_length"`
+	TickRate      int                 `json:"tick_rate"`
+	Collisions    game.CollisionRules `json:"collisions"`
+	Teams         map[string]string   `json:"teams,omitempty"`
+	Shrink        game.ShrinkRules    `json:"shrink"`
+	Items         game.ItemRules      `json:"items"`
+}
+
+type matchPlayerResult struct {
+	AccountID     string `json:"account_id"`
+	Placement     int    `json:"placement"`
+	Length        int    `json:"length"`
+	FoodEaten     int    `json:"food_eaten"`
+	Kills         int    `json:"kills"`
+	Bonus         int    `json:"bonus"`
+	SurvivalTicks int    `json:"survival_ticks"`
+	DeathCause    string `json:"death_cause,omitempty"`
+	KillerID      string `json:"killer_id,omitempty"`
+}
+
+type matchHistoryResult struct {
+	ID        string              `json:"id"`
+	MatchID   string              `json:"match_id"`
+	Map       json.RawMessage     `json:"map"`
+	Settings  json.RawMessage     `json:"settings"`
+	Ticks     int                 `json:"ticks"`
+	Duration  int64               `json:"duration"`
+	StartedAt time.Time           `json:"started_at"`
+	EndedAt   time.Time           `json:"ended_at"`
+	Players   []matchPlayerResult `json:"players"`
+}
+
+// newSeriesHistory describes a series that was just won by winner, a player
+// or a team when it was played in teams, in the round that ended with
+// result.
+func newSeriesHistory(matchID string, winner string, result game.Result) db.Series {
+	series := db.Series{
+		GameID:  matchID,
+		Length:  result.Series.Length,
+		Rounds:  result.Series.Rounds,
+		EndedAt: result.EndedAt.UTC(),
+		Players: make([]db.SeriesPlayer, 0, len(result.Series.Scores)),
+	}
+
+	if result.Series.InTeams() {
+		series.WinnerTeam = winner
+	} else {
+		series.WinnerID = winner
+	}
+
+	for _, score := range result.Series.Scores {
+		series.Players = append(series.Players, db.SeriesPlayer{
+			AccountID: score.PlayerID,
+			Team:      score.Team,
+			Points:    score.Points,
+		})
+	}
+
+	return series
+}
+
+// newMatchHistory describes a round of a match of mode that just ended to be
+// kept in the match history.
+func newMatchHistory(matchID string, mode string, playersLimit int, result game.Result, replay game.Replay) (db.Match, error) {
+	_map, err := json.Marshal(replay.Map)
+	if err != nil {
+		return db.Match{}, err
+	}
+
+	settings, err := json.Marshal(matchHistorySettings{
+		Mode:          mode,
+		PlayersLimit:  playersLimit,
+		FoodsLimit:    replay.FoodsLimit,
+		InitialLength: replay.InitialLength,
+		TickRate:      replay.TicksPerSecond,
+		Collisions:    replay.Collisions,
+		Teams:         replay.Teams,
+		Shrink:        replay.Shrink,
+		Items:         replay.Items,
+	})
+	if err != nil {
+		return db.Match{}, err
+	}
+
+	match := db.Match{
+		GameID:    matchID,
+		Map:       _map,
+		Settings:  settings,
+		Ticks:     int(result.Ticks),
+		Duration:  result.EndedAt.Sub(result.StartedAt),
+		StartedAt: result.StartedAt.UTC(),
+		EndedAt:   result.EndedAt.UTC(),
+		Players:   make([]db.MatchPlayer, 0, len(result.Scoreboard)),
+	}
+
+	for _, score := range result.Scoreboard {
+		match.Players = append(match.Players, db.MatchPlayer{
+			AccountID:     score.PlayerID,
+			Placement:     score.Placement,
+			Length:        score.Length,
+			FoodEaten:     score.FoodEaten,
+			Kills:         score.Kills,
+			Bonus:         score.Bonus,
+			SurvivalTicks: int(score.SurvivalTicks),
+			DeathCause:    string(score.Cause),
+			KillerID:      score.KillerID,
+		})
+	}
+
+	return match, nil
+}
+
+func parseMatchHistoryResult(match db.Match) matchHistoryResult {
+	result := matchHistoryResult{
+		ID:        match.ID,
+		MatchID:   match.GameID,
+		Map:       match.Map,
+		Settings:  match.Settings,
+		Ticks:     match.Ticks,
+		Duration:  match.Duration.Milliseconds(),
+		StartedAt: match.StartedAt,
+		EndedAt:   match.EndedAt,
+		Players:   make([]matchPlayerResult, 0, len(match.Players)),
+	}
+
+	for _, player := range match.Players {
+		result.Players = append(result.Players, matchPlayerResult{
+			AccountID:     player.AccountID,
+			Placement:     player.Placement,
+			Length:        player.Length,
+			FoodEaten:     player.FoodEaten,
+			Kills:         player.Kills,
+			Bonus:         player.Bonus,
+			SurvivalTicks: player.SurvivalTicks,
+			DeathCause:    player.DeathCause,
+			KillerID:      player.KillerID,
+		})
+	}
+
+	return result
+}
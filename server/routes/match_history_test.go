@@ -0,0 +1,76 @@
+package routes
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Maycon-Santos/go-snake-backend/game"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMatchHistory(t *testing.T) {
+	startedAt := time.Date(2023, 11, 12, 19, 0, 0, 0, time.UTC)
+
+	result := game.Result{
+		Ticks:     120,
+		StartedAt: startedAt,
+		EndedAt:   startedAt.Add(12 * time.Second),
+		Scoreboard: game.Scoreboard{
+			{PlayerID: "1", Length: 9, FoodEaten: 6, Kills: 1, Bonus: 5, SurvivalTicks: 120, Placement: 1},
+			{PlayerID: "2", Length: 4, FoodEaten: 1, SurvivalTicks: 80, Placement: 2, Cause: game.CauseSnake, KillerID: "1"},
+		},
+	}
+
+	replay := game.Replay{
+		TicksPerSecond: 10,
+		Rules: game.Rules{
+			Map:           game.Map{Tiles: game.Tiles{Horizontal: 20, Vertical: 10}, Edges: game.EdgesWrap},
+			FoodsLimit:    2,
+			InitialLength: 3,
+			Teams:         map[string]string{"1": "red", "2": "blue"},
+			Shrink:        game.ShrinkRules{Interval: 100, MinWidth: 5, MinHeight: 5},
+			Items:         game.DefaultItemRules(2, 10),
+		},
+	}
+
+	t.Run("should keep the duration, settings and scores of the round", func(t *testing.T) {
+		match, err := newMatchHistory("match", "custom", 4, result, replay)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "match", match.GameID)
+		assert.Equal(t, 120, match.Ticks)
+		assert.Equal(t, 12*time.Second, match.Duration)
+		assert.Len(t, match.Players, 2)
+		assert.Equal(t, "SNAKE", match.Players[1].DeathCause)
+		assert.Equal(t, "1", match.Players[1].KillerID)
+		assert.Empty(t, match.Players[0].DeathCause)
+
+		var settings matchHistorySettings
+		assert.NoError(t, json.Unmarshal(match.Settings, &settings))
+		assert.Equal(t, 4, settings.PlayersLimit)
+		assert.Equal(t, 10, settings.TickRate)
+		assert.Equal(t, 3, settings.InitialLength)
+		assert.Equal(t, "custom", settings.Mode)
+		assert.Equal(t, replay.Teams, settings.Teams)
+		assert.Equal(t, replay.Shrink, settings.Shrink)
+		assert.Equal(t, replay.Items, settings.Items)
+		assert.Equal(t, 5, match.Players[0].Bonus)
+	})
+
+	t.Run("should serve the round back as it was kept", func(t *testing.T) {
+		match, err := newMatchHistory("match", "custom", 4, result, replay)
+		assert.NoError(t, err)
+
+		match.ID = "7"
+		history := parseMatchHistoryResult(match)
+
+		assert.Equal(t, "7", history.ID)
+		assert.Equal(t, "match", history.MatchID)
+		assert.Equal(t, int64(12000), history.Duration)
+		assert.Equal(t, 1, history.Players[0].Placement)
+		assert.Equal(t, 1, history.Players[0].Kills)
+		assert.Equal(t, 5, history.Players[0].Bonus)
+		assert.JSONEq(t, string(match.Map), string(history.Map))
+	})
+}
@@ -0,0 +1,236 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"time"
+
+	"github.com/Maycon-Santos/go-snake-backend/container"
+	"github.com/Maycon-Santos/go-snake-backend/db"
+	"github.com/Maycon-Santos/go-snake-backend/game"
+	"github.com/Maycon-Santos/go-snake-backend/leaderboard"
+	"github.com/Maycon-Santos/go-snake-backend/process"
+	"github.com/Maycon-Santos/go-snake-backend/rating"
+	"github.com/Maycon-Santos/go-snake-backend/utils"
+)
+
+// customMatchMode is the mode of the matches created by the players, as
+// opposed to the ones formed by matchmaking.
+const customMatchMode = "custom"
+
+// matchServices is what the matches created by the server rely on once
+// they are running.
+type matchServices struct {
+	env               process.Env
+	matches           game.Matches
+	replaysRepository db.ReplaysRepository
+	matchesRepository db.MatchesRepository
+	ratingsRepository db.RatingsRepository
+	seriesRepository  db.SeriesRepository
+	leaderboards      leaderboard.Leaderboards
+}
+
+func retrieveMatchServices(container container.Container) matchServices {
+	var s matchServices
+
+	err := container.Retrieve(
+		&s.env,
+		&s.matches,
+		&s.replaysRepository,
+		&s.matchesRepository,
+		&s.ratingsRepository,
+		&s.seriesRepository,
+		&s.leaderboards,
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return s
+}
+
+// playedMap is the map a match with settings is played on. A shrinking arena
+// is walled, so no snake wraps around its edges.
+func playedMap(settings createMatchRequestBody, selectedMap game.Map) game.Map {
+	if settings.Shrink > 0 {
+		selectedMap.Edges = game.EdgesWall
+	}
+
+	return selectedMap
+}
+
+// newMatch adds a match of mode played with settings on selectedMap, which
+// are expected to be validated already. The match sends its state to the
+// players as it changes and keeps the replay, the history, the
+// leaderboards and the ratings of every round played to the end.
+func (s matchServices) newMatch(mode string, settings createMatchRequestBody, selectedMap game.Map) (game.Match, error) {
+	match, err := s.matches.Add(settings.PlayersLimit, 10)
+	if err != nil {
+		return nil, err
+	}
+
+	// The match outlives the request, so its handlers log with a context
+	// of their own.
+	matchCtx := withLogAttrs(context.Background(), slog.String("match_id", match.GetID()))
+
+	go func() {
+		logErrors(matchCtx, match, match.Done())
+
+		// A match may close on its own, after a panic in its loop.
+		s.matches.DeleteByID(match.GetID())
+	}()
+
+	reconnectMode := game.ControlFrozen
+	if s.env.Match.ReconnectMode == "autopilot" {
+		reconnectMode = game.ControlAutopilot
+	}
+
+	collisions := game.CollisionRules{
+		HeadOn:       game.HeadOnBothDie,
+		TailChasing:  settings.TailChasing,
+		FriendlyFire: settings.FriendlyFire,
+	}
+
+	if settings.HeadOn == "longer_wins" {
+		collisions.HeadOn = game.HeadOnLongerWins
+	}
+
+	selectedMap = playedMap(settings, selectedMap)
+
+	shrink := game.ShrinkRules{}
+	if settings.Shrink > 0 {
+		shrink = game.ShrinkRules{
+			Interval:  uint64(settings.Shrink * settings.TickRate),
+			Warning:   uint64(settings.ShrinkWarning * settings.TickRate),
+			MinWidth:  settings.MinArena,
+			MinHeight: settings.MinArena,
+		}
+	}
+
+	items := game.ItemRules{}
+	if settings.Items > 0 {
+		items = game.DefaultItemRules(settings.Items, settings.TickRate)
+	}
+
+	mapInput := &game.MapInput{
+		Name:  &selectedMap.Name,
+		Tiles: &selectedMap.Tiles,
+		Edges: &selectedMap.Edges,
+		Walls: selectedMap.Walls,
+	}
+
+	match.UpdateState(game.MatchStateInput{
+		FoodsLimit:      utils.Ptr(settings.FoodsLimit),
+		TickRate:        utils.Ptr(settings.TickRate),
+		InitialLength:   utils.Ptr(settings.InitialLength),
+		TimeLimit:       utils.Ptr(time.Duration(settings.TimeLimit) * time.Second),
+		Countdown:       utils.Ptr(time.Duration(settings.Countdown) * time.Second),
+		SuddenDeath:     utils.Ptr(time.Duration(settings.SuddenDeath) * time.Second),
+		ResultsDuration: utils.Ptr(time.Duration(settings.Results) * time.Second),
+		SeriesLength:    utils.Ptr(settings.Series),
+		Teams:           utils.Ptr(settings.Teams),
+		Shrink:          &shrink,
+		Items:           &items,
+		Collisions:      &collisions,
+		ReconnectGrace:  utils.Ptr(s.env.Match.ReconnectGrace),
+		ReconnectMode:   utils.Ptr(reconnectMode),
+		Map:             mapInput,
+		Mode:            utils.Ptr(mode),
+		Public:          utils.Ptr(settings.Public),
+	})
+
+	match.OnUpdateState(func() {
+		err := match.SendMessage(parseMatchMessage(match))
+		if err != nil {
+			handleError(matchCtx, err)
+		}
+	})
+
+	match.OnSnapshot(func(snapshot game.Snapshot) {
+		err := match.SendSnapshot(parseSnapshotMessage(snapshot), snapshot.Keyframe)
+		if err != nil {
+			handleError(matchCtx, err)
+		}
+	})
+
+	match.OnLeave(func(player game.Player) {
+		msg := parseRemovePlayer(player)
+		if player.IsSpectator() {
+			msg = parseMatchMessage(match)
+		}
+
+		if err := match.SendMessage(msg); err != nil {
+			handleError(matchCtx, err)
+		}
+
+		if len(match.GetPlayers()) == 0 {
+			s.matches.DeleteByID(match.GetID())
+		}
+	})
+
+	match.OnCountdown(func(remaining int) {
+		if err := match.SendMessage(parseCountdownMessage(remaining)); err != nil {
+			handleError(matchCtx, err)
+		}
+	})
+
+	match.OnScoreboard(func(scoreboard game.Scoreboard) {
+		if err := match.SendMessage(parseScoreboardMessage(scoreboard)); err != nil {
+			handleError(matchCtx, err)
+		}
+	})
+
+	match.OnEnd(func(result game.Result, replay game.Replay) {
+		if err := match.SendMessage(parseMatchResultMessage(result)); err != nil {
+			handleError(matchCtx, err)
+		}
+
+		go func() {
+			replayLog, err := json.Marshal(replay)
+			if err != nil {
+				handleError(matchCtx, err)
+				return
+			}
+
+			if _, err = s.replaysRepository.Save(matchCtx, match.GetID(), replayLog); err != nil {
+				handleError(matchCtx, err)
+			}
+		}()
+
+		go func() {
+			history, err := newMatchHistory(match.GetID(), match.GetMode(), match.GetPlayersLimit(), result, replay)
+			if err != nil {
+				handleError(matchCtx, err)
+				return
+			}
+
+			historyID, err := s.matchesRepository.Save(matchCtx, history)
+			if err != nil {
+				handleError(matchCtx, err)
+				return
+			}
+
+			if err = s.leaderboards.Record(matchCtx, history); err != nil {
+				handleError(matchCtx, err)
+			}
+
+			if err = rating.Rate(matchCtx, s.ratingsRepository, historyID, history); err != nil {
+				handleError(matchCtx, err)
+			}
+		}()
+
+		if winner, over := result.Series.Winner(); over && result.Series.Length > 1 {
+			go func() {
+				series := newSeriesHistory(match.GetID(), winner, result)
+
+				if _, err := s.seriesRepository.Save(matchCtx, series); err != nil {
+					handleError(matchCtx, err)
+				}
+			}()
+		}
+	})
+
+	return match, nil
+}
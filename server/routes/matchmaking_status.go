@@ -0,0 +1,83 @@
+package routes
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Maycon-Santos/go-snake-backend/container"
+	"github.com/Maycon-Santos/go-snake-backend/matchmaking"
+	"github.com/julienschmidt/httprouter"
+)
+
+// MatchmakingStatus streams where the account stands in the matchmaking
+// queue over a socket, as it waits and its search widens, until it is
+// matched, times out or leaves the queue. The socket is closed then.
+func MatchmakingStatus(container container.Container) httprouter.Handle {
+	var queue matchmaking.Queue
+
+	err := container.Retrieve(&queue)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		accountID := params.ByName("account_id")
+
+		// The socket outlives the request, so errors are handled with a
+		// context that is not canceled when the handler returns.
+		ctx := withLogAttrs(
+			context.WithoutCancel(request.Context()),
+			slog.String("player_id", accountID),
+		)
+
+		socket, err := upgradeSocket(writer, request)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+
+		defer socket.Close()
+
+		codec := socketCodec(socket)
+
+		closed := make(chan struct{})
+
+		go func() {
+			defer close(closed)
+
+			for {
+				if _, _, err := socket.NextReader(); err != nil {
+					return
+				}
+			}
+		}()
+
+		statuses, unsubscribe := queue.Subscribe(accountID)
+		defer unsubscribe()
+
+		for {
+			select {
+			case <-closed:
+				return
+			case status := <-statuses:
+				msgBytes, err := codec.Encode(parseMatchmakingMessage(status, time.Now()))
+				if err != nil {
+					handleError(ctx, err)
+					return
+				}
+
+				if err = socket.WriteMessage(codec.MessageType(), msgBytes); err != nil {
+					handleError(ctx, err)
+					return
+				}
+
+				if status.Status != matchmaking.StatusQueued {
+					return
+				}
+			}
+		}
+	}
+}
@@ -1,160 +1,686 @@
 package routes
 
 import (
-	"encoding/json"
+	"math"
+	"time"
 
 	"github.com/Maycon-Santos/go-snake-backend/db"
 	"github.com/Maycon-Santos/go-snake-backend/game"
+	"github.com/Maycon-Santos/go-snake-backend/matchmaking"
 )
 
 type tilesMessage struct {
-	Horizontal int `json:"horizontal"`
-	Vertical   int `json:"vertical"`
+	Horizontal int `json:"horizontal" bin:"1"`
+	Vertical   int `json:"vertical" bin:"2"`
+}
+
+type cellMessage struct {
+	X int `json:"x" bin:"1"`
+	Y int `json:"y" bin:"2"`
 }
 
 type mapMessage struct {
-	Tiles tilesMessage `json:"tiles"`
+	Name  string        `json:"name,omitempty" bin:"1"`
+	Tiles tilesMessage  `json:"tiles" bin:"2"`
+	Edges string        `json:"edges" bin:"3"`
+	Walls []cellMessage `json:"walls" bin:"4"`
+}
+
+type spectatorMessage struct {
+	ID       string `json:"id" bin:"1"`
+	Username string `json:"username" bin:"2"`
+}
+
+type settingsMessage struct {
+	PlayersLimit  int    `json:"playersLimit" bin:"1"`
+	FoodsLimit    int    `json:"foodsLimit" bin:"2"`
+	TickRate      int    `json:"tickRate" bin:"3"`
+	InitialLength int    `json:"initialLength" bin:"4"`
+	TimeLimit     int    `json:"timeLimit" bin:"5"`
+	Countdown     int    `json:"countdown" bin:"6"`
+	SuddenDeath   int    `json:"suddenDeath" bin:"7"`
+	Results       int    `json:"results" bin:"8"`
+	Series        int    `json:"series" bin:"9"`
+	Teams         int    `json:"teams" bin:"10"`
+	FriendlyFire  bool   `json:"friendlyFire" bin:"11"`
+	Shrink        int    `json:"shrink" bin:"12"`
+	ShrinkWarning int    `json:"shrinkWarning" bin:"13"`
+	MinArena      int    `json:"minArena" bin:"14"`
+	Items         int    `json:"items" bin:"15"`
+	HeadOn        string `json:"headOn" bin:"16"`
+	TailChasing   bool   `json:"tailChasing" bin:"17"`
 }
 
 type matchMessage struct {
-	ID     string     `json:"id"`
-	Status string     `json:"status"`
-	Map    mapMessage `json:"map"`
+	ID         string             `json:"id" bin:"1"`
+	Status     string             `json:"status" bin:"2"`
+	Map        mapMessage         `json:"map" bin:"3"`
+	Settings   settingsMessage    `json:"settings" bin:"4"`
+	Spectators []spectatorMessage `json:"spectators" bin:"5"`
 }
 
 type bodyFragmentMessage struct {
-	X int `json:"x"`
-	Y int `json:"y"`
+	X int `json:"x" bin:"1"`
+	Y int `json:"y" bin:"2"`
 }
 
 type playerMessage struct {
-	ID       string                `json:"id"`
-	Username string                `json:"username"`
-	Body     []bodyFragmentMessage `json:"body"`
-	Ready    bool                  `json:"ready"`
-	Alive    bool                  `json:"alive"`
+	ID        string                `json:"id" bin:"1"`
+	Username  string                `json:"username" bin:"2"`
+	Body      []bodyFragmentMessage `json:"body" bin:"3"`
+	Ready     bool                  `json:"ready" bin:"4"`
+	Alive     bool                  `json:"alive" bin:"5"`
+	Connected bool                  `json:"connected" bin:"6"`
+	Team      string                `json:"team,omitempty" bin:"7"`
+	TeamColor string                `json:"teamColor,omitempty" bin:"8"`
+	Effects   []effectMessage       `json:"effects,omitempty" bin:"9"`
+}
+
+type countdownMessage struct {
+	Remaining int `json:"remaining" bin:"1"`
+}
+
+type sessionMessage struct {
+	ResumeToken    string `json:"resumeToken" bin:"1"`
+	ReconnectGrace int64  `json:"reconnectGrace" bin:"2"`
 }
 
 type playerSkinMessage struct {
-	PlayerId string `json:"playerId"`
-	Color    string `json:"color"`
-	Pattern  string `json:"pattern"`
+	PlayerId string `json:"playerId" bin:"1"`
+	Color    string `json:"color" bin:"2"`
+	Pattern  string `json:"pattern" bin:"3"`
 }
 
 type foodPositionMessage struct {
-	X int `json:"x"`
-	Y int `json:"y"`
+	X int `json:"x" bin:"1"`
+	Y int `json:"y" bin:"2"`
 }
 
 type foodMessage struct {
-	ID       string              `json:"id"`
-	Position foodPositionMessage `json:"position"`
+	ID       string              `json:"id" bin:"1"`
+	Position foodPositionMessage `json:"position" bin:"2"`
+}
+
+// effectMessage is an item acting on a snake until the tick Until.
+type effectMessage struct {
+	Kind  string `json:"kind" bin:"1"`
+	Until uint64 `json:"until" bin:"2"`
+}
+
+// itemMessage is an item lying on the map until the tick ExpiresAt.
+type itemMessage struct {
+	ID        uint64 `json:"id" bin:"1"`
+	Kind      string `json:"kind" bin:"2"`
+	X         int    `json:"x" bin:"3"`
+	Y         int    `json:"y" bin:"4"`
+	ExpiresAt uint64 `json:"expiresAt" bin:"5"`
+}
+
+// snakeDeltaMessage carries Effects only when they changed, an empty list
+// meaning that no effect is acting on the snake anymore.
+type snakeDeltaMessage struct {
+	ID      string                `json:"id" bin:"1"`
+	Alive   bool                  `json:"alive" bin:"2"`
+	Head    []bodyFragmentMessage `json:"head,omitempty" bin:"3"`
+	Tail    int                   `json:"tail,omitempty" bin:"4"`
+	Body    []bodyFragmentMessage `json:"body,omitempty" bin:"5"`
+	Effects *[]effectMessage      `json:"effects,omitempty" bin:"6"`
+}
+
+type foodDeltaMessage struct {
+	Index    int                 `json:"index" bin:"1"`
+	Position foodPositionMessage `json:"position" bin:"2"`
+}
+
+// arenaMessage is the part of the map still playable in a shrinking arena,
+// every tile outside of it being a wall.
+type arenaMessage struct {
+	Left   int `json:"left" bin:"1"`
+	Top    int `json:"top" bin:"2"`
+	Right  int `json:"right" bin:"3"`
+	Bottom int `json:"bottom" bin:"4"`
+}
+
+// shrinkWarningMessage announces the arena left in Ticks ticks.
+type shrinkWarningMessage struct {
+	Arena arenaMessage `json:"arena" bin:"1"`
+	Ticks uint64       `json:"ticks" bin:"2"`
+}
+
+type snapshotMessage struct {
+	Seq           uint64                `json:"seq" bin:"1"`
+	Keyframe      bool                  `json:"keyframe" bin:"2"`
+	Players       []snakeDeltaMessage   `json:"players" bin:"3"`
+	Foods         []foodDeltaMessage    `json:"foods" bin:"4"`
+	Items         []itemMessage         `json:"items,omitempty" bin:"5"`
+	RemovedItems  []uint64              `json:"removedItems,omitempty" bin:"6"`
+	Arena         *arenaMessage         `json:"arena,omitempty" bin:"7"`
+	ShrinkWarning *shrinkWarningMessage `json:"shrinkWarning,omitempty" bin:"8"`
+}
+
+type scoreMessage struct {
+	PlayerID      string `json:"playerId" bin:"1"`
+	Length        int    `json:"length" bin:"2"`
+	FoodEaten     int    `json:"foodEaten" bin:"3"`
+	Bonus         int    `json:"bonus" bin:"4"`
+	Kills         int    `json:"kills" bin:"5"`
+	SurvivalTicks uint64 `json:"survivalTicks" bin:"6"`
+	Placement     int    `json:"placement" bin:"7"`
+	Cause         string `json:"cause,omitempty" bin:"8"`
+	KillerID      string `json:"killerId,omitempty" bin:"9"`
+}
+
+type scoreboardMessage struct {
+	Scores []scoreMessage `json:"scores" bin:"1"`
+}
+
+type seriesScoreMessage struct {
+	PlayerID string `json:"playerId" bin:"1"`
+	Team     string `json:"team,omitempty" bin:"2"`
+	Points   int    `json:"points" bin:"3"`
+}
+
+// seriesMessage is where the players of a best of Length stand after Round
+// rounds. Winner is set once the series is over, to a team when it is played
+// in teams.
+type seriesMessage struct {
+	Length int                  `json:"length" bin:"1"`
+	Round  int                  `json:"round" bin:"2"`
+	Scores []seriesScoreMessage `json:"scores" bin:"3"`
+	Winner string               `json:"winner,omitempty" bin:"4"`
+}
+
+type matchResultMessage struct {
+	Ticks  uint64         `json:"ticks" bin:"1"`
+	Scores []scoreMessage `json:"scores" bin:"2"`
+	Series *seriesMessage `json:"series,omitempty" bin:"3"`
+}
+
+// matchmakingMessage is where a player stands in the matchmaking queue.
+// Band is how far from their rating they accept players and Waited is
+// how many seconds they have been queued.
+type matchmakingMessage struct {
+	Status  string `json:"status" bin:"1"`
+	Mode    string `json:"mode" bin:"2"`
+	Region  string `json:"region,omitempty" bin:"3"`
+	Rating  int    `json:"rating" bin:"4"`
+	Band    int    `json:"band" bin:"5"`
+	Waited  int    `json:"waited" bin:"6"`
+	MatchID string `json:"matchId,omitempty" bin:"7"`
+}
+
+// lobbyMatchMessage describes a public match in the lobby. Map is empty for
+// the maps that are not named.
+type lobbyMatchMessage struct {
+	ID           string `json:"id" bin:"1"`
+	Owner        string `json:"owner" bin:"2"`
+	Players      int    `json:"players" bin:"3"`
+	PlayersLimit int    `json:"playersLimit" bin:"4"`
+	Status       string `json:"status" bin:"5"`
+	Map          string `json:"map" bin:"6"`
+	MapWidth     int    `json:"mapWidth" bin:"7"`
+	MapHeight    int    `json:"mapHeight" bin:"8"`
+	Mode         string `json:"mode" bin:"9"`
+}
+
+// lobbyMessage tells that a public match was ADDED to the lobby, was
+// UPDATED or was REMOVED from it.
+type lobbyMessage struct {
+	Event string            `json:"event" bin:"1"`
+	Match lobbyMatchMessage `json:"match" bin:"2"`
 }
 
 type message struct {
-	MatchData    *matchMessage      `json:"match,omitempty"`
-	Player       *playerMessage     `json:"player,omitempty"`
-	PlayerSkin   *playerSkinMessage `json:"playerSkin,omitempty"`
-	RemovePlayer string             `json:"removePlayer,omitempty"`
-	Food         *foodMessage       `json:"food,omitempty"`
+	MatchData    *matchMessage       `json:"match,omitempty" bin:"1"`
+	Player       *playerMessage      `json:"player,omitempty" bin:"2"`
+	PlayerSkin   *playerSkinMessage  `json:"playerSkin,omitempty" bin:"3"`
+	RemovePlayer string              `json:"removePlayer,omitempty" bin:"4"`
+	Food         *foodMessage        `json:"food,omitempty" bin:"5"`
+	Snapshot     *snapshotMessage    `json:"snapshot,omitempty" bin:"6"`
+	Session      *sessionMessage     `json:"session,omitempty" bin:"7"`
+	Scoreboard   *scoreboardMessage  `json:"scoreboard,omitempty" bin:"8"`
+	MatchResult  *matchResultMessage `json:"matchResult,omitempty" bin:"9"`
+	Matchmaking  *matchmakingMessage `json:"matchmaking,omitempty" bin:"10"`
+	Lobby        *lobbyMessage       `json:"lobby,omitempty" bin:"11"`
+	Countdown    *countdownMessage   `json:"countdown,omitempty" bin:"12"`
 }
 
-func parseMatchMessage(match game.Match) ([]byte, error) {
-	mapTiles := match.GetMap().Tiles
+// headOnMessage names the head-on rule the way the edges are named, with the
+// rule rounds that predate it were played with.
+func headOnMessage(collisions game.CollisionRules) string {
+	if collisions.HeadOn == "" {
+		return string(game.HeadOnBothDie)
+	}
 
-	msg := message{
-		MatchData: &matchMessage{
-			ID:     match.GetID(),
-			Status: string(match.GetStatus()),
-			Map: mapMessage{
-				Tiles: tilesMessage{
-					Horizontal: mapTiles.Horizontal,
-					Vertical:   mapTiles.Vertical,
-				},
+	return string(collisions.HeadOn)
+}
+
+func newMatchMessage(id string, status string, _map game.Map, settings settingsMessage) *matchMessage {
+	edges := string(_map.Edges)
+	if edges == "" {
+		edges = string(game.EdgesWrap)
+	}
+
+	walls := make([]cellMessage, 0, len(_map.Walls))
+	for _, wall := range _map.Walls {
+		walls = append(walls, cellMessage{
+			X: wall.X,
+			Y: wall.Y,
+		})
+	}
+
+	return &matchMessage{
+		ID:     id,
+		Status: status,
+		Map: mapMessage{
+			Name: _map.Name,
+			Tiles: tilesMessage{
+				Horizontal: _map.Tiles.Horizontal,
+				Vertical:   _map.Tiles.Vertical,
 			},
+			Edges: edges,
+			Walls: walls,
 		},
+		Settings:   settings,
+		Spectators: make([]spectatorMessage, 0),
 	}
+}
 
-	msgBytes, err := json.Marshal(msg)
-	if err != nil {
-		return nil, err
+func newBodyFragmentsMessage(body []game.BodyFragment) []bodyFragmentMessage {
+	fragments := make([]bodyFragmentMessage, 0, len(body))
+
+	for _, fragment := range body {
+		fragments = append(fragments, bodyFragmentMessage{
+			X: fragment.X,
+			Y: fragment.Y,
+		})
 	}
 
-	return msgBytes, nil
+	return fragments
 }
 
-func parsePlayerMessage(player game.Player) ([]byte, error) {
-	msg := message{
-		Player: &playerMessage{
-			ID:       player.GetID(),
-			Username: player.GetName(),
-			Ready:    player.IsReady(),
-			Alive:    player.IsAlive(),
-			Body:     make([]bodyFragmentMessage, 0),
+func newPlayerMessage(id, username string, body []game.BodyFragment, ready, alive bool) *playerMessage {
+	return &playerMessage{
+		ID:        id,
+		Username:  username,
+		Ready:     ready,
+		Alive:     alive,
+		Connected: true,
+		Body:      newBodyFragmentsMessage(body),
+		Effects:   make([]effectMessage, 0),
+	}
+}
+
+// setTeam puts the player on team, along with the color it is drawn with.
+func (pm *playerMessage) setTeam(team string) {
+	pm.Team = team
+	pm.TeamColor = game.TeamColor(team)
+}
+
+func newFoodMessage(x, y int) *foodMessage {
+	return &foodMessage{
+		Position: foodPositionMessage{
+			X: x,
+			Y: y,
 		},
 	}
+}
 
-	for _, fragment := range player.GetBody() {
-		msg.Player.Body = append(msg.Player.Body, bodyFragmentMessage{
-			X: fragment.X,
-			Y: fragment.Y,
+func parseMatchMessage(match game.Match) message {
+	msg := message{
+		MatchData: newMatchMessage(match.GetID(), string(match.GetStatus()), match.GetMap(), settingsMessage{
+			PlayersLimit:  match.GetPlayersLimit(),
+			FoodsLimit:    match.GetFoodsLimit(),
+			TickRate:      match.GetTickRate(),
+			InitialLength: match.GetInitialLength(),
+			TimeLimit:     int(match.GetTimeLimit().Seconds()),
+			Countdown:     int(match.GetCountdown().Seconds()),
+			SuddenDeath:   int(match.GetSuddenDeath().Seconds()),
+			Results:       int(match.GetResultsDuration().Seconds()),
+			Series:        match.GetSeriesLength(),
+			Teams:         match.GetTeams(),
+			FriendlyFire:  match.GetCollisionRules().FriendlyFire,
+			Shrink:        shrinkSeconds(match.GetShrinkRules().Interval, match.GetTickRate()),
+			ShrinkWarning: shrinkSeconds(match.GetShrinkRules().Warning, match.GetTickRate()),
+			MinArena:      match.GetShrinkRules().MinWidth,
+			Items:         match.GetItemRules().Limit,
+			HeadOn:        headOnMessage(match.GetCollisionRules()),
+			TailChasing:   match.GetCollisionRules().TailChasing,
+		}),
+	}
+
+	for _, spectator := range match.GetSpectators() {
+		msg.MatchData.Spectators = append(msg.MatchData.Spectators, spectatorMessage{
+			ID:       spectator.GetID(),
+			Username: spectator.GetName(),
 		})
 	}
 
-	msgBytes, err := json.Marshal(msg)
-	if err != nil {
-		return nil, err
+	return msg
+}
+
+func parsePlayerMessage(player game.Player) message {
+	msg := message{
+		Player: newPlayerMessage(
+			player.GetID(),
+			player.GetName(),
+			player.GetBody(),
+			player.IsReady(),
+			player.IsAlive(),
+		),
 	}
 
-	return msgBytes, nil
+	msg.Player.Connected = player.IsConnected()
+	msg.Player.setTeam(player.GetTeam())
+	msg.Player.Effects = newEffectMessages(player.GetEffects())
+
+	return msg
 }
 
-func parsePlayerSkin(player game.Player, skin db.Skin) ([]byte, error) {
+// parseSessionMessage tells a player how to take its place back after losing
+// the connection: reconnecting with the resume token, within the grace given
+// in milliseconds.
+func parseSessionMessage(player game.Player, reconnectGrace time.Duration) message {
+	msg := message{
+		Session: &sessionMessage{
+			ResumeToken:    player.GetResumeToken(),
+			ReconnectGrace: reconnectGrace.Milliseconds(),
+		},
+	}
+
+	return msg
+}
+
+func parsePlayerSkin(playerID string, skin db.Skin) message {
 	msg := message{
 		PlayerSkin: &playerSkinMessage{
-			PlayerId: player.GetID(),
+			PlayerId: playerID,
 			Color:    skin.ColorID,
 			Pattern:  skin.PatternID,
 		},
 	}
 
-	msgBytes, err := json.Marshal(msg)
-	if err != nil {
-		return nil, err
+	return msg
+}
+
+func parseRemovePlayer(player game.Player) message {
+	msg := message{
+		RemovePlayer: player.GetID(),
 	}
 
-	return msgBytes, nil
+	return msg
 }
 
-func parseRemovePlayer(player game.Player) ([]byte, error) {
+func parseFoodMessage(food game.Food) message {
+	foodPosition := food.GetPosition()
+
 	msg := message{
-		RemovePlayer: player.GetID(),
+		Food: newFoodMessage(foodPosition.X, foodPosition.Y),
 	}
 
-	msgBytes, err := json.Marshal(msg)
-	if err != nil {
-		return nil, err
+	return msg
+}
+
+func parseSnakeMessage(snake game.Snake, username string) message {
+	msg := message{
+		Player: newPlayerMessage(snake.PlayerID, username, snake.Body, false, snake.Alive),
 	}
 
-	return msgBytes, nil
+	msg.Player.setTeam(snake.Team)
+	msg.Player.Effects = newEffectMessages(snake.Effects)
+
+	return msg
 }
 
-func parseFoodMessage(food game.Food) ([]byte, error) {
-	foodPosition := food.GetPosition()
+func newEffectMessages(effects []game.Effect) []effectMessage {
+	msgs := make([]effectMessage, 0, len(effects))
 
+	for _, effect := range effects {
+		msgs = append(msgs, effectMessage{
+			Kind:  string(effect.Kind),
+			Until: effect.Until,
+		})
+	}
+
+	return msgs
+}
+
+// shrinkSeconds is how many seconds ticks of the shrink rules last.
+func shrinkSeconds(ticks uint64, tickRate int) int {
+	if tickRate <= 0 {
+		return 0
+	}
+
+	return int(ticks) / tickRate
+}
+
+// replayTeams counts the teams the players of a replay were split in.
+func replayTeams(replay game.Replay) int {
+	teams := make(map[string]bool)
+	for _, team := range replay.Teams {
+		teams[team] = true
+	}
+
+	return len(teams)
+}
+
+func parseReplayMatchMessage(matchID string, status string, replay game.Replay) message {
+	msg := message{
+		MatchData: newMatchMessage(matchID, status, replay.Map, settingsMessage{
+			PlayersLimit:  len(replay.Players),
+			FoodsLimit:    replay.FoodsLimit,
+			TickRate:      replay.TickRate(),
+			InitialLength: replay.InitialLength,
+			HeadOn:        headOnMessage(replay.Collisions),
+			TailChasing:   replay.Collisions.TailChasing,
+			FriendlyFire:  replay.Collisions.FriendlyFire,
+			Teams:         replayTeams(replay),
+			Shrink:        shrinkSeconds(replay.Shrink.Interval, replay.TickRate()),
+			ShrinkWarning: shrinkSeconds(replay.Shrink.Warning, replay.TickRate()),
+			MinArena:      replay.Shrink.MinWidth,
+			Items:         replay.Items.Limit,
+		}),
+	}
+
+	return msg
+}
+
+func newScoreMessages(scores []game.Score) []scoreMessage {
+	msgs := make([]scoreMessage, 0, len(scores))
+
+	for _, score := range scores {
+		msgs = append(msgs, scoreMessage{
+			PlayerID:      score.PlayerID,
+			Length:        score.Length,
+			FoodEaten:     score.FoodEaten,
+			Bonus:         score.Bonus,
+			Kills:         score.Kills,
+			SurvivalTicks: score.SurvivalTicks,
+			Placement:     score.Placement,
+			Cause:         string(score.Cause),
+			KillerID:      score.KillerID,
+		})
+	}
+
+	return msgs
+}
+
+// parseScoreboardMessage lists the scores of a running round, by placement.
+func parseScoreboardMessage(scoreboard game.Scoreboard) message {
+	msg := message{
+		Scoreboard: &scoreboardMessage{
+			Scores: newScoreMessages(scoreboard.Standings()),
+		},
+	}
+
+	return msg
+}
+
+// parseCountdownMessage tells the seconds left before a round starts.
+func parseCountdownMessage(remaining int) message {
+	msg := message{
+		Countdown: &countdownMessage{
+			Remaining: remaining,
+		},
+	}
+
+	return msg
+}
+
+// parseMatchResultMessage tells how a round ended and, when the match plays
+// series of more than one round, the series standings.
+func parseMatchResultMessage(result game.Result) message {
 	msg := message{
-		Food: &foodMessage{
+		MatchResult: &matchResultMessage{
+			Ticks:  result.Ticks,
+			Scores: newScoreMessages(result.Scoreboard.Standings()),
+		},
+	}
+
+	if result.Series.Length > 1 {
+		msg.MatchResult.Series = newSeriesMessage(result.Series)
+	}
+
+	return msg
+}
+
+func newSeriesMessage(series game.Series) *seriesMessage {
+	msg := &seriesMessage{
+		Length: series.Length,
+		Round:  series.Rounds,
+		Scores: make([]seriesScoreMessage, 0, len(series.Scores)),
+	}
+
+	for _, score := range series.Standings() {
+		msg.Scores = append(msg.Scores, seriesScoreMessage{
+			PlayerID: score.PlayerID,
+			Team:     score.Team,
+			Points:   score.Points,
+		})
+	}
+
+	msg.Winner, _ = series.Winner()
+
+	return msg
+}
+
+func parseSnapshotMessage(snapshot game.Snapshot) message {
+	msg := message{
+		Snapshot: &snapshotMessage{
+			Seq:      snapshot.Seq,
+			Keyframe: snapshot.Keyframe,
+			Players:  make([]snakeDeltaMessage, 0, len(snapshot.Snakes)),
+			Foods:    make([]foodDeltaMessage, 0, len(snapshot.Foods)),
+		},
+	}
+
+	for _, snake := range snapshot.Snakes {
+		delta := snakeDeltaMessage{
+			ID:    snake.PlayerID,
+			Alive: snake.Alive,
+			Tail:  snake.Tail,
+		}
+
+		if len(snake.Head) > 0 {
+			delta.Head = newBodyFragmentsMessage(snake.Head)
+		}
+
+		if snapshot.Keyframe {
+			delta.Body = newBodyFragmentsMessage(snake.Body)
+		}
+
+		if snake.Effects != nil {
+			effects := newEffectMessages(*snake.Effects)
+			delta.Effects = &effects
+		}
+
+		msg.Snapshot.Players = append(msg.Snapshot.Players, delta)
+	}
+
+	for _, food := range snapshot.Foods {
+		msg.Snapshot.Foods = append(msg.Snapshot.Foods, foodDeltaMessage{
+			Index: food.Index,
 			Position: foodPositionMessage{
-				X: foodPosition.X,
-				Y: foodPosition.Y,
+				X: food.Position.X,
+				Y: food.Position.Y,
 			},
+		})
+	}
+
+	for _, item := range snapshot.Items {
+		msg.Snapshot.Items = append(msg.Snapshot.Items, itemMessage{
+			ID:        item.ID,
+			Kind:      string(item.Kind),
+			X:         item.Position.X,
+			Y:         item.Position.Y,
+			ExpiresAt: item.ExpiresAt,
+		})
+	}
+
+	if len(snapshot.RemovedItems) > 0 {
+		msg.Snapshot.RemovedItems = snapshot.RemovedItems
+	}
+
+	if snapshot.Arena != nil {
+		arena := arenaMessage(*snapshot.Arena)
+		msg.Snapshot.Arena = &arena
+	}
+
+	if snapshot.Warning != nil {
+		msg.Snapshot.ShrinkWarning = &shrinkWarningMessage{
+			Arena: arenaMessage(snapshot.Warning.Arena),
+			Ticks: snapshot.Warning.Ticks,
+		}
+	}
+
+	return msg
+}
+
+func parseMatchmakingMessage(status matchmaking.Status, now time.Time) message {
+	msg := message{
+		Matchmaking: &matchmakingMessage{
+			Status:  string(status.Status),
+			Mode:    status.Ticket.Mode,
+			Region:  status.Ticket.Region,
+			Rating:  int(math.Round(status.Ticket.Rating)),
+			Band:    int(math.Round(status.Band)),
+			MatchID: status.MatchID,
 		},
 	}
 
-	msgBytes, err := json.Marshal(msg)
-	if err != nil {
-		return nil, err
+	if status.Status == matchmaking.StatusQueued {
+		msg.Matchmaking.Waited = int(now.Sub(status.Ticket.EnqueuedAt).Seconds())
 	}
 
-	return msgBytes, nil
+	return msg
+}
+
+func newLobbyMatchMessage(match game.Match) lobbyMatchMessage {
+	_map := match.GetMap()
+
+	msg := lobbyMatchMessage{
+		ID:           match.GetID(),
+		Players:      len(match.GetPlayers()),
+		PlayersLimit: match.GetPlayersLimit(),
+		Status:       string(match.GetStatus()),
+		Map:          _map.Name,
+		MapWidth:     _map.Tiles.Horizontal,
+		MapHeight:    _map.Tiles.Vertical,
+		Mode:         match.GetMode(),
+	}
+
+	if owner := match.GetOwner(); owner != nil {
+		msg.Owner = owner.GetName()
+	}
+
+	return msg
+}
+
+func parseLobbyMessage(event string, match lobbyMatchMessage) message {
+	return message{
+		Lobby: &lobbyMessage{
+			Event: event,
+			Match: match,
+		},
+	}
 }
@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"net/url"
+	"strconv"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// parsePagination reads the ?limit= and ?offset= of a paginated list.
+func parsePagination(query url.Values) (int, int) {
+	limit := parseQueryInt(query.Get("limit"), defaultPageSize, 1, maxPageSize)
+	offset := parseQueryInt(query.Get("offset"), 0, 0, -1)
+
+	return limit, offset
+}
+
+// parseQueryInt reads a number from the query string, falling back to
+// defaultValue when it is missing or invalid and clamping it to min and, if
+// it is not negative, max.
+func parseQueryInt(value string, defaultValue, min, max int) int {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return defaultValue
+	}
+
+	if n < min {
+		return min
+	}
+
+	if max >= 0 && n > max {
+		return max
+	}
+
+	return n
+}
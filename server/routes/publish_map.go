@@ -0,0 +1,133 @@
+package routes
+
+import (
+	"log"
+	"log/slog"
+	"net/http"
+
+	"github.com/Maycon-Santos/go-snake-backend/container"
+	"github.com/Maycon-Santos/go-snake-backend/db"
+	"github.com/julienschmidt/httprouter"
+)
+
+// PublishMap makes a map of the account available to every account. The map
+// is validated again in case the rules changed since it was saved.
+func PublishMap(container container.Container) httprouter.Handle {
+	var mapsRepository db.MapsRepository
+
+	err := container.Retrieve(&mapsRepository)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		accountID := params.ByName("account_id")
+		mapID := params.ByName("map_id")
+		ctx := withLogAttrs(request.Context(), slog.String("map_id", mapID))
+
+		storedMap, err := mapsRepository.GetByID(request.Context(), mapID)
+		if err != nil {
+			handleError(ctx, err)
+
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusInternalServerError,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_UNKNOWN,
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(ctx, err)
+			}
+
+			return
+		}
+
+		if storedMap == nil || storedMap.OwnerID != accountID {
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusNotFound,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_MAP_NOT_FOUND,
+					Message: "map not found",
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(ctx, err)
+			}
+
+			return
+		}
+
+		_map, err := decodeMapDefinition(*storedMap)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+
+		responseType, err := validateMapLayout(_map, storedMap.PlayersLimit, defaultCreateMatchRequestBody.InitialLength)
+		if err != nil {
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusForbidden,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    responseType,
+					Message: err.Error(),
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(ctx, err)
+			}
+
+			return
+		}
+
+		if _, err := mapsRepository.Publish(request.Context(), mapID, accountID); err != nil {
+			handleError(ctx, err)
+
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusInternalServerError,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_UNKNOWN,
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(ctx, err)
+			}
+
+			return
+		}
+
+		storedMap.Published = true
+
+		result, err := parseMapResponseResult(*storedMap)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+
+		response := responseConfig{
+			Body: responseBody{
+				Success: true,
+				Result:  result,
+			},
+		}
+
+		if err := makeResponse(request.Context(), writer, response); err != nil {
+			handleError(ctx, err)
+		}
+	}
+}
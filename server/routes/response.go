@@ -46,6 +46,63 @@ const (
 	TYPE_PATTERN_NOT_AVAILABLE = responseType("PATTERN_NOT_AVAILABLE")
 
 	TYPE_MATCH_NOT_FOUND = responseType("MATCH_NOT_FOUND")
+	TYPE_MATCH_RESERVED  = responseType("MATCH_RESERVED")
+
+	TYPE_PLAYERS_LIMIT_BELOW_MIN  = responseType("PLAYERS_LIMIT_BELOW_MIN")
+	TYPE_PLAYERS_LIMIT_ABOVE_MAX  = responseType("PLAYERS_LIMIT_ABOVE_MAX")
+	TYPE_MAP_WIDTH_BELOW_MIN      = responseType("MAP_WIDTH_BELOW_MIN")
+	TYPE_MAP_WIDTH_ABOVE_MAX      = responseType("MAP_WIDTH_ABOVE_MAX")
+	TYPE_MAP_HEIGHT_BELOW_MIN     = responseType("MAP_HEIGHT_BELOW_MIN")
+	TYPE_MAP_HEIGHT_ABOVE_MAX     = responseType("MAP_HEIGHT_ABOVE_MAX")
+	TYPE_FOODS_LIMIT_BELOW_MIN    = responseType("FOODS_LIMIT_BELOW_MIN")
+	TYPE_FOODS_LIMIT_ABOVE_MAX    = responseType("FOODS_LIMIT_ABOVE_MAX")
+	TYPE_TICK_RATE_BELOW_MIN      = responseType("TICK_RATE_BELOW_MIN")
+	TYPE_TICK_RATE_ABOVE_MAX      = responseType("TICK_RATE_ABOVE_MAX")
+	TYPE_EDGES_INVALID            = responseType("EDGES_INVALID")
+	TYPE_MAP_NOT_FOUND            = responseType("MAP_NOT_FOUND")
+	TYPE_INITIAL_LENGTH_BELOW_MIN = responseType("INITIAL_LENGTH_BELOW_MIN")
+	TYPE_INITIAL_LENGTH_ABOVE_MAX = responseType("INITIAL_LENGTH_ABOVE_MAX")
+	TYPE_TIME_LIMIT_BELOW_MIN     = responseType("TIME_LIMIT_BELOW_MIN")
+	TYPE_TIME_LIMIT_ABOVE_MAX     = responseType("TIME_LIMIT_ABOVE_MAX")
+	TYPE_COUNTDOWN_BELOW_MIN      = responseType("COUNTDOWN_BELOW_MIN")
+	TYPE_COUNTDOWN_ABOVE_MAX      = responseType("COUNTDOWN_ABOVE_MAX")
+	TYPE_SUDDEN_DEATH_BELOW_MIN   = responseType("SUDDEN_DEATH_BELOW_MIN")
+	TYPE_SUDDEN_DEATH_ABOVE_MAX   = responseType("SUDDEN_DEATH_ABOVE_MAX")
+	TYPE_RESULTS_BELOW_MIN        = responseType("RESULTS_BELOW_MIN")
+	TYPE_RESULTS_ABOVE_MAX        = responseType("RESULTS_ABOVE_MAX")
+	TYPE_SERIES_BELOW_MIN         = responseType("SERIES_BELOW_MIN")
+	TYPE_SERIES_ABOVE_MAX         = responseType("SERIES_ABOVE_MAX")
+	TYPE_TEAMS_BELOW_MIN          = responseType("TEAMS_BELOW_MIN")
+	TYPE_TEAMS_ABOVE_MAX          = responseType("TEAMS_ABOVE_MAX")
+	TYPE_SHRINK_BELOW_MIN         = responseType("SHRINK_BELOW_MIN")
+	TYPE_SHRINK_ABOVE_MAX         = responseType("SHRINK_ABOVE_MAX")
+	TYPE_SHRINK_WARNING_BELOW_MIN = responseType("SHRINK_WARNING_BELOW_MIN")
+	TYPE_SHRINK_WARNING_ABOVE_MAX = responseType("SHRINK_WARNING_ABOVE_MAX")
+	TYPE_MIN_ARENA_BELOW_MIN      = responseType("MIN_ARENA_BELOW_MIN")
+	TYPE_MIN_ARENA_ABOVE_MAX      = responseType("MIN_ARENA_ABOVE_MAX")
+	TYPE_MIN_ARENA_ABOVE_MAP      = responseType("MIN_ARENA_ABOVE_MAP")
+	TYPE_ITEMS_BELOW_MIN          = responseType("ITEMS_BELOW_MIN")
+	TYPE_ITEMS_ABOVE_MAX          = responseType("ITEMS_ABOVE_MAX")
+	TYPE_HEAD_ON_INVALID          = responseType("HEAD_ON_INVALID")
+
+	TYPE_REPLAY_NOT_FOUND = responseType("REPLAY_NOT_FOUND")
+
+	TYPE_MAP_NAME_MISSING       = responseType("MAP_NAME_MISSING")
+	TYPE_MAP_NAME_BELOW_MIN_LEN = responseType("MAP_NAME_BELOW_MIN_LEN")
+	TYPE_MAP_NAME_ABOVE_MAX_LEN = responseType("MAP_NAME_ABOVE_MAX_LEN")
+	TYPE_MAP_INVALID            = responseType("MAP_INVALID")
+	TYPE_MAP_SPAWN_BLOCKED      = responseType("MAP_SPAWN_BLOCKED")
+	TYPE_MAP_SPAWN_UNREACHABLE  = responseType("MAP_SPAWN_UNREACHABLE")
+	TYPE_MAP_NO_FOOD_SPACE      = responseType("MAP_NO_FOOD_SPACE")
+	TYPE_MAP_PUBLISHED          = responseType("MAP_PUBLISHED")
+
+	TYPE_LEADERBOARD_BOARD_INVALID  = responseType("LEADERBOARD_BOARD_INVALID")
+	TYPE_LEADERBOARD_SEASON_INVALID = responseType("LEADERBOARD_SEASON_INVALID")
+
+	TYPE_MATCHMAKING_MODE_INVALID         = responseType("MATCHMAKING_MODE_INVALID")
+	TYPE_MATCHMAKING_REGION_ABOVE_MAX_LEN = responseType("MATCHMAKING_REGION_ABOVE_MAX_LEN")
+	TYPE_MATCHMAKING_ALREADY_QUEUED       = responseType("MATCHMAKING_ALREADY_QUEUED")
+	TYPE_MATCHMAKING_NOT_QUEUED           = responseType("MATCHMAKING_NOT_QUEUED")
 )
 
 func makeResponse(ctx context.Context, writer http.ResponseWriter, response responseConfig) error {
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/Maycon-Santos/go-snake-backend/game"
+	"github.com/gorilla/websocket"
+)
+
+// binaryProtocol is the websocket subprotocol a client asks for to receive
+// and send messages with binaryCodec instead of JSON. Its version changes
+// with every change to the layout of the messages; the layout of each
+// version is kept in testdata.
+const binaryProtocol = "snake.v1.bin"
+
+func upgradeSocket(writer http.ResponseWriter, request *http.Request) (*websocket.Conn, error) {
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		Subprotocols:    []string{binaryProtocol},
+		CheckOrigin:     func(r *http.Request) bool { return true },
+	}
+
+	return upgrader.Upgrade(writer, request, nil)
+}
+
+func socketCodec(socket *websocket.Conn) game.Codec {
+	if socket.Subprotocol() == binaryProtocol {
+		return binaryCodec{}
+	}
+
+	return game.JSONCodec
+}
@@ -0,0 +1,188 @@
+package routes
+
+import (
+	"encoding/json"
+	"log"
+	"log/slog"
+	"net/http"
+
+	"github.com/Maycon-Santos/go-snake-backend/container"
+	"github.com/Maycon-Santos/go-snake-backend/db"
+	"github.com/julienschmidt/httprouter"
+)
+
+// UpdateMap replaces a draft of the account. Published maps can no longer be
+// changed, since matches may be played on them.
+func UpdateMap(container container.Container) httprouter.Handle {
+	var mapsRepository db.MapsRepository
+
+	err := container.Retrieve(&mapsRepository)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		accountID := params.ByName("account_id")
+		mapID := params.ByName("map_id")
+		ctx := withLogAttrs(request.Context(), slog.String("map_id", mapID))
+
+		var requestBody mapRequestBody
+
+		if err := json.NewDecoder(request.Body).Decode(&requestBody); err != nil {
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusUnprocessableEntity,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_PAYLOAD_INVALID,
+					Message: "payload is invalid",
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(ctx, err)
+			}
+
+			return
+		}
+
+		storedMap, err := mapsRepository.GetByID(request.Context(), mapID)
+		if err != nil {
+			handleError(ctx, err)
+
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusInternalServerError,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_UNKNOWN,
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(ctx, err)
+			}
+
+			return
+		}
+
+		if storedMap == nil || storedMap.OwnerID != accountID {
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusNotFound,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_MAP_NOT_FOUND,
+					Message: "map not found",
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(ctx, err)
+			}
+
+			return
+		}
+
+		if storedMap.Published {
+			err := errMapPublished
+
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusForbidden,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_MAP_PUBLISHED,
+					Message: err.Error(),
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(ctx, err)
+			}
+
+			return
+		}
+
+		_map, responseType, err := parseMapRequestBody(requestBody)
+		if err != nil {
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusForbidden,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    responseType,
+					Message: err.Error(),
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(ctx, err)
+			}
+
+			return
+		}
+
+		storedMap, err = saveMap(request.Context(), mapsRepository, mapID, accountID, requestBody.PlayersLimit, _map)
+		if err == errMapPublished {
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusForbidden,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_MAP_PUBLISHED,
+					Message: err.Error(),
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(ctx, err)
+			}
+
+			return
+		}
+
+		if err != nil {
+			handleError(ctx, err)
+
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusInternalServerError,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_UNKNOWN,
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(ctx, err)
+			}
+
+			return
+		}
+
+		result, err := parseMapResponseResult(*storedMap)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+
+		response := responseConfig{
+			Body: responseBody{
+				Success: true,
+				Result:  result,
+			},
+		}
+
+		if err := makeResponse(request.Context(), writer, response); err != nil {
+			handleError(ctx, err)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Maycon-Santos/go-snake-backend/container"
+	"github.com/julienschmidt/httprouter"
+)
+
+type validateMapResponseResult struct {
+	Edges string   `json:"edges"`
+	Grid  []string `json:"grid"`
+}
+
+// ValidateMap checks a map drawn in the map editor without storing it.
+func ValidateMap(container container.Container) httprouter.Handle {
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		var requestBody mapRequestBody
+
+		if err := json.NewDecoder(request.Body).Decode(&requestBody); err != nil {
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusUnprocessableEntity,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_PAYLOAD_INVALID,
+					Message: "payload is invalid",
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(request.Context(), err)
+			}
+
+			return
+		}
+
+		_map, responseType, err := parseMapRequestBody(requestBody)
+		if err != nil {
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusForbidden,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    responseType,
+					Message: err.Error(),
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(request.Context(), err)
+			}
+
+			return
+		}
+
+		response := responseConfig{
+			Body: responseBody{
+				Success: true,
+				Result: validateMapResponseResult{
+					Edges: requestBody.Edges,
+					Grid:  _map.Grid(),
+				},
+			},
+		}
+
+		if err := makeResponse(request.Context(), writer, response); err != nil {
+			handleError(request.Context(), err)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package routes
+
+import (
+	"encoding/json"
+	"log"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Maycon-Santos/go-snake-backend/container"
+	"github.com/Maycon-Santos/go-snake-backend/db"
+	"github.com/Maycon-Santos/go-snake-backend/game"
+	"github.com/julienschmidt/httprouter"
+)
+
+func WatchReplay(container container.Container) httprouter.Handle {
+	var (
+		replaysRepository db.ReplaysRepository
+		skinsRepository   db.SkinsRepository
+	)
+
+	err := container.Retrieve(&replaysRepository, &skinsRepository)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		replayID := params.ByName("replay_id")
+		ctx := withLogAttrs(request.Context(), slog.String("replay_id", replayID))
+
+		storedReplay, err := replaysRepository.GetByID(request.Context(), replayID)
+		if err != nil {
+			handleError(ctx, err)
+
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusInternalServerError,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_UNKNOWN,
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(ctx, err)
+			}
+
+			return
+		}
+
+		if storedReplay == nil {
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusNotFound,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_REPLAY_NOT_FOUND,
+					Message: "replay not found",
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(ctx, err)
+			}
+
+			return
+		}
+
+		var replay game.Replay
+
+		if err := json.Unmarshal(storedReplay.Log, &replay); err != nil {
+			handleError(ctx, err)
+
+			response := responseConfig{
+				Header: responseHeader{
+					Status: http.StatusInternalServerError,
+				},
+				Body: responseBody{
+					Success: false,
+					Type:    TYPE_UNKNOWN,
+				},
+			}
+
+			if err := makeResponse(request.Context(), writer, response); err != nil {
+				handleError(ctx, err)
+			}
+
+			return
+		}
+
+		socket, err := upgradeSocket(writer, request)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+
+		defer socket.Close()
+
+		codec := socketCodec(socket)
+
+		closed := make(chan struct{})
+
+		go func() {
+			defer close(closed)
+
+			for {
+				if _, _, err := socket.NextReader(); err != nil {
+					return
+				}
+			}
+		}()
+
+		send := func(msg message) {
+			msgBytes, err := codec.Encode(msg)
+			if err != nil {
+				handleError(ctx, err)
+				return
+			}
+
+			if err = socket.WriteMessage(codec.MessageType(), msgBytes); err != nil {
+				handleError(ctx, err)
+			}
+		}
+
+		usernames := make(map[string]string)
+		for _, player := range replay.Players {
+			usernames[player.ID] = player.Name
+		}
+
+		ticker := time.NewTicker(time.Second / time.Duration(replay.TickRate()))
+		defer ticker.Stop()
+
+		var prev game.World
+
+		replay.Play(func(world game.World, events []game.Event) bool {
+			defer func() {
+				prev = world
+			}()
+
+			if world.Tick == 0 {
+				send(parseReplayMatchMessage(storedReplay.MatchID, string(game.StatusRunning), replay))
+
+				for _, snake := range world.Snakes {
+					send(parseSnakeMessage(snake, usernames[snake.PlayerID]))
+
+					skin, err := skinsRepository.GetAccountSkin(request.Context(), snake.PlayerID)
+					if err != nil {
+						handleError(ctx, err)
+						continue
+					}
+
+					if skin != nil {
+						send(parsePlayerSkin(snake.PlayerID, *skin))
+					}
+				}
+
+				send(parseSnapshotMessage(game.NewKeyframe(world)))
+
+				return true
+			}
+
+			select {
+			case <-closed:
+				return false
+			case <-ticker.C:
+			}
+
+			send(parseSnapshotMessage(game.Diff(prev, world)))
+
+			return true
+		})
+
+		send(parseReplayMatchMessage(storedReplay.MatchID, string(game.StatusResults), replay))
+	}
+}
@@ -8,12 +8,26 @@ import (
 	"github.com/Maycon-Santos/go-snake-backend/process"
 )
 
+// Listen serves the API on the server port and, when a metrics address is
+// set, the metrics on a listener of their own. It returns as soon as one of
+// them stops.
 func Listen(container container.Container) error {
 	var env process.Env
 
 	container.Retrieve(&env)
 
 	routes := newRoutes(container)
+	errs := make(chan error, 2)
 
-	return http.ListenAndServe(fmt.Sprintf(":%d", env.ServerPort), routes)
+	if env.MetricsAddress != "" {
+		go func() {
+			errs <- http.ListenAndServe(env.MetricsAddress, newMetricsRoutes())
+		}()
+	}
+
+	go func() {
+		errs <- http.ListenAndServe(fmt.Sprintf(":%d", env.ServerPort), routes)
+	}()
+
+	return <-errs
 }
@@ -6,6 +6,9 @@ type Validator interface {
 	MinLen(len uint) Validator
 	MaxLen(len uint) Validator
 	NoContains(noContains []string) Validator
+	Min(min float64) Validator
+	Max(max float64) Validator
+	OneOf(values []string) Validator
 }
 
 func Field(field string) Validator {
@@ -42,3 +45,18 @@ func (v validatorConfig) NoContains(noContains []string) Validator {
 	v.noContains = noContains
 	return v
 }
+
+func (v validatorConfig) Min(min float64) Validator {
+	v.min = &min
+	return v
+}
+
+func (v validatorConfig) Max(max float64) Validator {
+	v.max = &max
+	return v
+}
+
+func (v validatorConfig) OneOf(values []string) Validator {
+	v.oneOf = values
+	return v
+}
@@ -12,6 +12,9 @@ type validatorConfig struct {
 	minLen     *uint
 	maxLen     *uint
 	noContains []string
+	min        *float64
+	max        *float64
+	oneOf      []string
 }
 
 type errType = string
@@ -25,6 +28,9 @@ const (
 	MinLen     errType = "minLen"
 	MaxLen     errType = "maxLen"
 	NoContains errType = "noContains"
+	Min        errType = "min"
+	Max        errType = "max"
+	OneOf      errType = "oneOf"
 )
 
 func init() {
@@ -34,6 +40,9 @@ func init() {
 		minLen,
 		maxLen,
 		noContains,
+		minValue,
+		maxValue,
+		oneOf,
 	)
 }
 
@@ -110,3 +119,58 @@ func noContains(config validatorConfig, field string, value interface{}) (errTyp
 
 	return "", nil
 }
+
+func numberValue(value interface{}) float64 {
+	reflectValue := reflect.ValueOf(value)
+
+	switch reflectValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(reflectValue.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(reflectValue.Uint())
+	case reflect.Float32, reflect.Float64:
+		return reflectValue.Float()
+	}
+
+	panic("unsupported type. Must be a number")
+}
+
+func minValue(config validatorConfig, field string, value interface{}) (errType, error) {
+	if config.min == nil {
+		return "", nil
+	}
+
+	if numberValue(value) < *config.min {
+		return Min, fmt.Errorf("the %s field must be at least %v", field, *config.min)
+	}
+
+	return "", nil
+}
+
+func maxValue(config validatorConfig, field string, value interface{}) (errType, error) {
+	if config.max == nil {
+		return "", nil
+	}
+
+	if numberValue(value) > *config.max {
+		return Max, fmt.Errorf("the %s field must be at most %v", field, *config.max)
+	}
+
+	return "", nil
+}
+
+func oneOf(config validatorConfig, field string, value interface{}) (errType, error) {
+	if config.oneOf == nil {
+		return "", nil
+	}
+
+	valueString := fmt.Sprint(value)
+
+	for _, option := range config.oneOf {
+		if valueString == option {
+			return "", nil
+		}
+	}
+
+	return OneOf, fmt.Errorf("the %s field must be one of the following: %s", field, strings.Join(config.oneOf, ", "))
+}